@@ -1,74 +1,397 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"html/template"
 	"log"
+	"maps"
+	"net/http"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/pdok/atom-generator/feeds"
+	"github.com/pdok/atom-generator/server"
 	"github.com/urfave/cli/v2"
-	"gopkg.in/yaml.v3"
 )
 
 const FILE string = `file`
 const OUTPUT string = `output`
+const SKIPDATA string = `skip-data`
+const WORKERS string = `workers`
+const TIMEOUT string = `timeout`
+const RETRIES string = `retries`
+const MAXCONNSPERHOST string = `max-conns-per-host`
+const UPDATEDFROMDATA string = `updated-from-data`
+const EXTENTFROMDATA string = `extent-from-data`
+const CACHE string = `cache`
+const MAXAGE string = `max-age`
+const OFFLINE string = `offline`
+const CHECKSUMATTR string = `checksum-attr`
+const CHECKSUMNAMESPACE string = `checksum-namespace`
+const CHECKSUMSIDECAR string = `checksum-sidecar`
+const ADDR string = `addr`
+const STATIC string = `static`
+const STREAM string = `stream`
+const HTML string = `html`
+const HTMLTEMPLATE string = `html-template`
 
 func main() {
 	app := cli.NewApp()
 	app.Name = "Atom Generator"
 	app.Usage = "A Golang Atom generation application"
 
-	app.Flags = []cli.Flag{
-		&cli.StringFlag{
-			Name:     FILE,
-			Aliases:  []string{"f"},
-			Usage:    "Config file",
-			Required: true,
-			EnvVars:  []string{"FILE"},
+	fileFlag := &cli.StringSliceFlag{
+		Name:    FILE,
+		Aliases: []string{"f"},
+		Usage:   "Config file or directory of config files, can be repeated",
+		EnvVars: []string{"FILE"},
+	}
+
+	defaults := feeds.DefaultResolverOptions()
+	dataFlags := []cli.Flag{
+		&cli.IntFlag{
+			Name:    WORKERS,
+			Usage:   "Number of data links that are resolved concurrently",
+			Value:   defaults.Workers,
+			EnvVars: []string{"WORKERS"},
+		},
+		&cli.DurationFlag{
+			Name:    TIMEOUT,
+			Usage:   "Timeout of a single data link lookup",
+			Value:   defaults.Timeout,
+			EnvVars: []string{"TIMEOUT"},
+		},
+		&cli.IntFlag{
+			Name:    RETRIES,
+			Usage:   "Number of retries of a failed data link lookup",
+			Value:   defaults.Retries,
+			EnvVars: []string{"RETRIES"},
+		},
+		&cli.IntFlag{
+			Name:    MAXCONNSPERHOST,
+			Usage:   "Maximum number of connections per host for the data link lookups",
+			Value:   defaults.MaxConnsPerHost,
+			EnvVars: []string{"MAX_CONNS_PER_HOST"},
+		},
+		&cli.BoolFlag{
+			Name:    UPDATEDFROMDATA,
+			Usage:   "Derive the updated of entries without one from the Last-Modified of their data links",
+			EnvVars: []string{"UPDATED_FROM_DATA"},
+		},
+		&cli.BoolFlag{
+			Name:    EXTENTFROMDATA,
+			Usage:   "Derive the bbox of data links and the polygon of entries without one from local GML and GeoPackage data",
+			EnvVars: []string{"EXTENT_FROM_DATA"},
 		},
 		&cli.StringFlag{
-			Name:     OUTPUT,
-			Aliases:  []string{"o"},
-			Usage:    "Output directory",
-			Required: true,
-			EnvVars:  []string{"OUTPUT"},
+			Name:    CACHE,
+			Usage:   "File in which the lookups and checksums of the data links are cached between runs",
+			EnvVars: []string{"CACHE"},
+		},
+		&cli.DurationFlag{
+			Name:    MAXAGE,
+			Usage:   "Duration for which a cached data link lookup is used without revalidating it",
+			EnvVars: []string{"MAX_AGE"},
+		},
+		&cli.BoolFlag{
+			Name:    OFFLINE,
+			Usage:   "Resolve the data links from the cache only, without any requests",
+			EnvVars: []string{"OFFLINE"},
 		},
 	}
 
-	app.Action = func(c *cli.Context) error {
+	app.Flags = append([]cli.Flag{
+		fileFlag,
+		&cli.StringFlag{
+			Name:    OUTPUT,
+			Aliases: []string{"o"},
+			Usage:   "Output directory",
+			EnvVars: []string{"OUTPUT"},
+		},
+		&cli.StringFlag{
+			Name:    CHECKSUMATTR,
+			Usage:   "Link attribute for the SHA-256 checksum of the data links, e.g. checksum:sha256",
+			EnvVars: []string{"CHECKSUM_ATTR"},
+		},
+		&cli.StringFlag{
+			Name:    CHECKSUMNAMESPACE,
+			Usage:   "Namespace of the prefix of the checksum attribute",
+			EnvVars: []string{"CHECKSUM_NAMESPACE"},
+		},
+		&cli.BoolFlag{
+			Name:    CHECKSUMSIDECAR,
+			Usage:   "Write the SHA-256 checksums of the data links to a .sha256 file next to the feed",
+			EnvVars: []string{"CHECKSUM_SIDECAR"},
+		},
+		&cli.BoolFlag{
+			Name:    HTML,
+			Usage:   "Write a HTML page next to every feed",
+			EnvVars: []string{"HTML"},
+		},
+		&cli.StringFlag{
+			Name:    HTMLTEMPLATE,
+			Usage:   "Go html/template file for the HTML pages instead of the embedded one, implies --html",
+			EnvVars: []string{"HTML_TEMPLATE"},
+		},
+	}, dataFlags...)
 
-		// Read and unmarshal config file
-		doc, err := os.ReadFile(c.String(FILE))
-		if err != nil {
-			log.Fatalf("error: %v, with file: %v", err, `no file`)
+	app.Action = func(c *cli.Context) error {
+		// the flags are not marked as required, because that would also require them for the subcommands
+		if len(c.StringSlice(FILE)) == 0 || c.String(OUTPUT) == `` {
+			return errors.New(`required flags "file, output" not set`)
 		}
-		var config feeds.Feeds
-		if err := yaml.Unmarshal(doc, &config); err != nil {
-			log.Fatalf("error: %v", err)
+
+		var htmlTemplate *template.Template
+		if c.String(HTMLTEMPLATE) != `` {
+			var err error
+			if htmlTemplate, err = feeds.LoadHTMLTemplate(c.String(HTMLTEMPLATE)); err != nil {
+				log.Fatalf("error: %v", err)
+			}
 		}
 
-		processedFeeds := feeds.ProcessFeeds(config)
+		config := readConfig(c.StringSlice(FILE))
+		processOptions, cache := options(c, config)
+		_, processedFeeds := process(config, processOptions, cache)
+
+		// validate all feeds before writing any of them
+		if !validate(processedFeeds) {
+			log.Fatalf(`ATOM Feeds are not valid`)
+		}
 
 		// write both service and dataset feeds
 		for _, feed := range processedFeeds {
-			if err := feed.Valid(); err != nil {
-				log.Fatalf(`ATOM Feeds with the id: %s is not valid. With the error: %s`, feed.ID, err.Error())
-			}
-
 			filename, err := feed.GetFileName()
-			if err == nil {
-				feed.WriteATOM(c.String(OUTPUT) + `/` + filename)
-			} else {
+			if err != nil {
 				log.Fatalf(`ATOM Feed NOT generated the id: %s`, feed.ID)
 			}
+			if err := feed.WriteATOM(c.String(OUTPUT) + `/` + filename); err != nil {
+				log.Fatalf("error: %v", err)
+			}
+			if c.Bool(CHECKSUMSIDECAR) && len(feed.Checksums()) > 0 {
+				if err := feed.WriteChecksums(c.String(OUTPUT) + `/` + filename + `.sha256`); err != nil {
+					log.Fatalf("error: %v", err)
+				}
+			}
+			if c.Bool(HTML) || htmlTemplate != nil {
+				html, err := feed.HTMLFileName()
+				if err != nil {
+					log.Fatalf("error: %v", err)
+				}
+				if err := feed.WriteHTML(c.String(OUTPUT)+`/`+html, htmlTemplate); err != nil {
+					log.Fatalf("error: %v", err)
+				}
+			}
+		}
+
+		// the OpenSearch descriptions are written next to the service feeds, one for all language variants
+		descriptions := feeds.OpenSearchDescriptions(processedFeeds)
+		for _, href := range slices.Sorted(maps.Keys(descriptions)) {
+			if opensearch, ok := feeds.OpenSearchFileName(href); ok {
+				if err := descriptions[href].WriteXML(c.String(OUTPUT) + `/` + opensearch); err != nil {
+					log.Fatalf("error: %v", err)
+				}
+			}
 		}
 
 		log.Println(`ATOM Feeds generated`)
 		return nil
 	}
 
+	app.Commands = []*cli.Command{
+		{
+			Name:      "validate",
+			Usage:     "Validate the config file without writing any ATOM Feeds",
+			UsageText: "atom validate -f config.yaml",
+			Flags: append([]cli.Flag{
+				&cli.StringSliceFlag{
+					Name:     fileFlag.Name,
+					Aliases:  fileFlag.Aliases,
+					Usage:    fileFlag.Usage,
+					EnvVars:  fileFlag.EnvVars,
+					Required: true,
+				},
+				&cli.BoolFlag{
+					Name:    SKIPDATA,
+					Usage:   "Skip the HEAD lookups of the data links",
+					EnvVars: []string{"SKIP_DATA"},
+				},
+			}, dataFlags...),
+			Action: func(c *cli.Context) error {
+				config := readConfig(c.StringSlice(FILE))
+				processOptions, cache := options(c, config)
+				_, processedFeeds := process(config, processOptions, cache)
+
+				if !validate(processedFeeds) {
+					log.Fatalf(`ATOM Feeds are not valid`)
+				}
+
+				log.Println(`ATOM Feeds are valid`)
+				return nil
+			},
+		},
+		{
+			Name:      "config",
+			Usage:     "Print the resolved config, with the variables, languages and defaults applied",
+			UsageText: "atom config -f config.yaml",
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{
+					Name:     fileFlag.Name,
+					Aliases:  fileFlag.Aliases,
+					Usage:    fileFlag.Usage,
+					EnvVars:  fileFlag.EnvVars,
+					Required: true,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				config, err := readConfig(c.StringSlice(FILE)).Resolved()
+				if err != nil {
+					log.Fatalf("error: %v", err)
+				}
+				b, err := config.GenerateYAML()
+				if err != nil {
+					log.Fatalf("error: %v", err)
+				}
+				_, err = os.Stdout.Write(b)
+				return err
+			},
+		},
+		{
+			Name:      "serve",
+			Usage:     "Serve the ATOM Feeds and answer the OpenSearch download operations",
+			UsageText: "atom serve -f config.yaml --addr=:8080",
+			Flags: append([]cli.Flag{
+				&cli.StringSliceFlag{
+					Name:     fileFlag.Name,
+					Aliases:  fileFlag.Aliases,
+					Usage:    fileFlag.Usage,
+					EnvVars:  fileFlag.EnvVars,
+					Required: true,
+				},
+				&cli.BoolFlag{
+					Name:    SKIPDATA,
+					Usage:   "Skip the HEAD lookups of the data links",
+					EnvVars: []string{"SKIP_DATA"},
+				},
+				&cli.StringFlag{
+					Name:    ADDR,
+					Usage:   "Address on which the ATOM Feeds are served",
+					Value:   ":8080",
+					EnvVars: []string{"ADDR"},
+				},
+				&cli.StringFlag{
+					Name:    STATIC,
+					Usage:   "Directory with static files, like the stylesheet, that are served next to the ATOM Feeds",
+					EnvVars: []string{"STATIC"},
+				},
+				&cli.BoolFlag{
+					Name:    STREAM,
+					Usage:   "Stream the data of a Get Spatial Dataset request instead of redirecting to the href",
+					EnvVars: []string{"STREAM"},
+				},
+			}, dataFlags...),
+			Action: func(c *cli.Context) error {
+				config := readConfig(c.StringSlice(FILE))
+				processOptions, cache := options(c, config)
+				generated, processedFeeds := process(config, processOptions, cache)
+
+				if !validate(processedFeeds) {
+					log.Fatalf(`ATOM Feeds are not valid`)
+				}
+
+				handler, err := server.New(processedFeeds, server.Options{
+					Static:   c.String(STATIC),
+					Stream:   c.Bool(STREAM),
+					Data:     server.DataByHref(generated),
+					Resolver: processOptions.Resolver,
+				})
+				if err != nil {
+					log.Fatalf("error: %v", err)
+				}
+
+				log.Printf(`serving ATOM Feeds on %s`, c.String(ADDR))
+				srv := &http.Server{Addr: c.String(ADDR), Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+				return srv.ListenAndServe()
+			},
+		},
+	}
+
 	err := app.Run(os.Args)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 }
+
+// readConfig reads and merges the config files
+func readConfig(files []string) feeds.Feeds {
+	config, err := feeds.LoadFeeds(files...)
+	if err != nil {
+		log.Fatalf("error: %v, with files: %v", err, files)
+	}
+	return config
+}
+
+// options returns the feeds.Options based on the flags and the config, and the cache that is used
+func options(c *cli.Context, config feeds.Feeds) (feeds.Options, *feeds.Cache) {
+	resolverOptions := feeds.DefaultResolverOptions()
+	resolverOptions.Workers = c.Int(WORKERS)
+	resolverOptions.Timeout = c.Duration(TIMEOUT)
+	resolverOptions.Retries = c.Int(RETRIES)
+	resolverOptions.MaxConnsPerHost = c.Int(MAXCONNSPERHOST)
+	resolverOptions.S3 = config.S3
+	resolverOptions.MaxAge = c.Duration(MAXAGE)
+	resolverOptions.Offline = c.Bool(OFFLINE)
+
+	if c.String(CACHE) != `` {
+		cache, err := feeds.LoadCache(c.String(CACHE))
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		resolverOptions.Cache = cache
+	} else if resolverOptions.Offline {
+		log.Fatalf(`error: --%s requires --%s`, OFFLINE, CACHE)
+	}
+
+	processOptions := feeds.Options{
+		SkipData:        c.Bool(SKIPDATA),
+		UpdatedFromData: c.Bool(UPDATEDFROMDATA),
+		ExtentFromData:  c.Bool(EXTENTFROMDATA),
+		Resolver:        feeds.NewResolver(resolverOptions),
+	}
+	if c.String(CHECKSUMATTR) != `` || c.Bool(CHECKSUMSIDECAR) {
+		processOptions.Checksums = &feeds.ChecksumOptions{
+			Attr:      c.String(CHECKSUMATTR),
+			Namespace: c.String(CHECKSUMNAMESPACE),
+		}
+	}
+	return processOptions, resolverOptions.Cache
+}
+
+// process expands the generators and processes the feeds, the cache is saved also when some of the feeds could not be processed
+// it returns the config with the generated entries and the processed feeds
+func process(config feeds.Feeds, processOptions feeds.Options, cache *feeds.Cache) (feeds.Feeds, []feeds.Feed) {
+	generated, generateErr := config.Generated(context.Background(), processOptions)
+	processedFeeds, err := feeds.ProcessFeeds(generated, processOptions)
+	if err := cache.Save(); err != nil {
+		log.Printf("could not save the cache: %v", err)
+	}
+	if err := errors.Join(generateErr, err); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	return generated, processedFeeds
+}
+
+// validate prints all the findings and returns false when one of them is an error
+func validate(processedFeeds []feeds.Feed) bool {
+	report := feeds.Feeds{Feeds: processedFeeds}.Valid()
+	for _, finding := range report.Findings {
+		log.Println(finding)
+	}
+	if report.HasErrors() {
+		log.Printf(`found %d error(s) and %d warning(s)`, len(report.Errors()), len(report.Warnings()))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package feeds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cache keeps what is known about the `data` sources between runs, the resolved Resources and their checksums,
+// it is stored as JSON on disk, a nil *Cache caches nothing
+type Cache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// cacheEntry is the cached state of a single `data` source
+type cacheEntry struct {
+	Resource
+	SHA256 string `json:"sha256,omitempty"`
+	// Resolved is the time at which the Resource was last resolved or revalidated
+	Resolved time.Time `json:"resolved,omitzero"`
+}
+
+// LoadCache reads the Cache from the given file, a missing file results in an empty Cache
+func LoadCache(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[string]cacheEntry)}
+
+	doc, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(doc, &c.entries); err != nil {
+		return nil, fmt.Errorf("could not unmarshal cache %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the Cache to its file, the file is replaced at once so an interrupted run leaves the previous Cache intact
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	doc, err := json.MarshalIndent(c.entries, "", " ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+`.*`)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(doc); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// resource returns the cached Resource of the uri and the time at which it was resolved
+func (c *Cache) resource(uri string) (Resource, time.Time, bool) {
+	if c == nil {
+		return Resource{}, time.Time{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[uri]
+	if !ok || entry.Resolved.IsZero() {
+		return Resource{}, time.Time{}, false
+	}
+	return entry.Resource, entry.Resolved, true
+}
+
+// setResource caches the Resource of the uri, the cached checksum is kept when the resource is unchanged
+func (c *Cache) setResource(uri string, resource Resource, resolved time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entries[uri]
+	if !entry.unchanged(resource) {
+		entry.SHA256 = ``
+	}
+	entry.Resource = resource
+	entry.Resolved = resolved
+	c.entries[uri] = entry
+}
+
+// checksum returns the cached SHA-256 of the uri, when the resource is unchanged since it was computed
+func (c *Cache) checksum(uri string, resource Resource) (string, bool) {
+	if c == nil {
+		return ``, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[uri]
+	if !ok || entry.SHA256 == `` || !entry.unchanged(resource) {
+		return ``, false
+	}
+	return entry.SHA256, true
+}
+
+// setChecksum caches the SHA-256 of the uri together with the resource it was computed for
+func (c *Cache) setChecksum(uri string, resource Resource, sum string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entries[uri]
+	entry.Resource = resource
+	entry.SHA256 = sum
+	c.entries[uri] = entry
+}
+
+// unchanged returns true when the resource has the same ETag or, without an ETag, the same Last-Modified and length
+func (e cacheEntry) unchanged(resource Resource) bool {
+	if resource.ETag != `` {
+		return e.ETag == resource.ETag
+	}
+	return !resource.LastModified.IsZero() && e.LastModified.Equal(resource.LastModified) && e.Length == resource.Length
+}
@@ -0,0 +1,81 @@
+package feeds
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResolverCache(t *testing.T) {
+	var requests, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/gml+xml")
+		w.Header().Set("Content-Length", "34987")
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	cache, err := LoadCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uri := server.URL + "/waternetwork_25832.gml"
+	expected := Resource{Length: "34987", Type: "application/gml+xml", ETag: `"v1"`}
+
+	var tests = []struct {
+		maxAge      time.Duration
+		offline     bool
+		uri         string
+		valid       bool
+		requests    int32
+		notModified int32
+	}{
+		// the first lookup fills the cache
+		0: {uri: uri, valid: true, requests: 1},
+		// a cached lookup younger than the max age is used without a request
+		1: {maxAge: time.Hour, uri: uri, valid: true, requests: 1},
+		// an older cached lookup is revalidated with a conditional request
+		2: {uri: uri, valid: true, requests: 2, notModified: 1},
+		// offline everything is resolved from the cache
+		3: {offline: true, uri: uri, valid: true, requests: 2, notModified: 1},
+		4: {offline: true, uri: server.URL + "/missing.gml", valid: false, requests: 2, notModified: 1},
+	}
+
+	for k, test := range tests {
+		options := testOptions()
+		options.Cache = cache
+		options.MaxAge = test.maxAge
+		options.Offline = test.offline
+
+		resource, err := NewResolver(options).Resolve(context.Background(), test.uri)
+		if (err == nil) != test.valid {
+			t.Errorf("test: %d, expected valid: %t \ngot: %v", k, test.valid, err)
+		}
+		if test.valid && resource != expected {
+			t.Errorf("test: %d, expected: %+v \ngot: %+v", k, expected, resource)
+		}
+		if requests.Load() != test.requests || notModified.Load() != test.notModified {
+			t.Errorf("test: %d, expected %d request(s) of which %d not modified, got: %d and %d",
+				k, test.requests, test.notModified, requests.Load(), notModified.Load())
+		}
+
+		// every run starts with the cache as it was saved by the previous run
+		if err := cache.Save(); err != nil {
+			t.Fatal(err)
+		}
+		if cache, err = LoadCache(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package feeds
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ChecksumOptions configures the SHA-256 checksums of the `data` links
+type ChecksumOptions struct {
+	// Attr is the name of the link attribute that holds the checksum, e.g. `checksum:sha256`,
+	// when empty the checksums are only available through Feed.Checksums
+	Attr string
+	// Namespace is declared on the feed for the prefix of Attr
+	Namespace string
+}
+
+// Checksum downloads a `data` source and returns its hex encoded SHA-256, failed downloads are retried with a backoff
+// the download is not limited by the Timeout of the Resolver, because large files can take longer
+func (r *Resolver) Checksum(ctx context.Context, uri string) (string, error) {
+	return retry(ctx, r.options, func() (string, error) {
+		return r.checksum(ctx, uri)
+	})
+}
+
+// ChecksumAll computes the checksums of the resolved `data` sources concurrently,
+// sources that are unchanged since their checksum was cached are not downloaded again
+func (r *Resolver) ChecksumAll(ctx context.Context, resources map[string]Resource) (map[string]string, map[string]error) {
+	uris := make([]string, 0, len(resources))
+	for uri := range resources {
+		uris = append(uris, uri)
+	}
+	return forAll(r.options.Workers, uris, func(uri string) (string, error) {
+		if sum, ok := r.options.Cache.checksum(uri, resources[uri]); ok {
+			return sum, nil
+		}
+		if r.options.Offline {
+			return ``, fmt.Errorf("the checksum of %s is not in the cache", uri)
+		}
+		sum, err := r.Checksum(ctx, uri)
+		if err != nil {
+			return ``, err
+		}
+		r.options.Cache.setChecksum(uri, resources[uri], sum)
+		return sum, nil
+	})
+}
+
+// checksum downloads the uri once and hashes its content while it is streamed
+func (r *Resolver) checksum(ctx context.Context, uri string) (string, error) {
+	u, src, err := r.source(uri)
+	if err != nil {
+		return ``, err
+	}
+	body, err := src.get(ctx, u)
+	if err != nil {
+		return ``, err
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return ``, &retryableError{err: err}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// applyChecksum writes the checksum of the link to the configured attribute
+func (l *Link) applyChecksum(options ChecksumOptions) Link {
+	if l.SHA256 != nil && options.Attr != `` {
+		l.Attrs = append(l.Attrs, xml.Attr{Name: xml.Name{Local: options.Attr}, Value: *l.SHA256})
+	}
+	return *l
+}
+
+// namespace returns the declaration of the namespace of the checksum attribute, e.g. xmlns:checksum
+func (o ChecksumOptions) namespace() (xml.Attr, bool) {
+	prefix, _, ok := strings.Cut(o.Attr, `:`)
+	if !ok || o.Namespace == `` {
+		return xml.Attr{}, false
+	}
+	return xml.Attr{Name: xml.Name{Local: `xmlns:` + prefix}, Value: o.Namespace}, true
+}
+
+// Checksums returns the checksums of the links of the entries in the format of sha256sum,
+// one line per link with the checksum and the href
+func (f *Feed) Checksums() []byte {
+	var b strings.Builder
+	for _, entry := range f.Entry {
+		for _, link := range entry.Link {
+			if link.SHA256 != nil {
+				b.WriteString(*link.SHA256 + `  ` + link.Href + "\n")
+			}
+		}
+	}
+	return []byte(b.String())
+}
+
+// WriteChecksums writes the checksums of the links of the entries to a sidecar file
+//
+//nolint:gosec
+func (f *Feed) WriteChecksums(filename string) error {
+	if err := os.WriteFile(filename, f.Checksums(), 0777); err != nil {
+		return &ProcessError{FeedID: f.ID, Err: fmt.Errorf("could not write to file %s: %w", filename, err)}
+	}
+	return nil
+}
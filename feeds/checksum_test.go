@@ -0,0 +1,100 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProcessFeedsChecksums(t *testing.T) {
+	content := "<FeatureCollection/>"
+	var downloads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Method == http.MethodGet {
+			downloads.Add(1)
+			_, _ = w.Write([]byte(content))
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "waternetwork_25832.zip"), []byte("PK\x03\x04"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	input := Feeds{Feeds: []Feed{{
+		ID: "http://xyz.org/data/abc/waternetwork.xml",
+		Entry: []Entry{{
+			ID: "http://xyz.org/data/abc/waternetwork_25832",
+			Link: []Link{
+				{Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Data: sp(server.URL + "/waternetwork_25832.gml")},
+				{Href: "http://xyz.org/data/abc/waternetwork_25832.zip", Data: sp(filepath.Join(dir, "waternetwork_25832.zip"))},
+				{Href: "http://xyz.org/data/abc/waternetwork_25832.json", Type: "application/json", SHA256: sp("0000")},
+			},
+		}},
+	}}}
+	checksums := &ChecksumOptions{Attr: "checksum:sha256", Namespace: "http://xyz.org/checksum"}
+	expected := []string{sha256Hex(content), sha256Hex("PK\x03\x04"), "0000"}
+
+	cache, err := LoadCache(filepath.Join(dir, "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolverOptions := testOptions()
+	resolverOptions.Cache = cache
+	for run := range 2 {
+		output, err := ProcessFeeds(input, Options{Checksums: checksums, Resolver: NewResolver(resolverOptions)})
+		if err != nil {
+			t.Fatalf("run: %d, unexpected error: %v", run, err)
+		}
+
+		var got []string
+		for _, link := range output[0].Entry[0].Link {
+			if !reflect.DeepEqual(link.Attrs, []xml.Attr{{Name: xml.Name{Local: "checksum:sha256"}, Value: *link.SHA256}}) {
+				t.Errorf("run: %d, unexpected attributes: %v", run, link.Attrs)
+			}
+			got = append(got, *link.SHA256)
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("run: %d, expected: %v \ngot: %v", run, expected, got)
+		}
+		if !reflect.DeepEqual(output[0].Attrs, []xml.Attr{{Name: xml.Name{Local: "xmlns:checksum"}, Value: "http://xyz.org/checksum"}}) {
+			t.Errorf("run: %d, expected the namespace declaration, got: %v", run, output[0].Attrs)
+		}
+		if run == 0 {
+			b, err := output[0].GenerateATOM()
+			if err != nil || !strings.Contains(string(b), `checksum:sha256="`+sha256Hex(content)+`"`) {
+				t.Errorf("expected the checksum attribute in the feed, got: %s, %v", b, err)
+			}
+			sidecar := string(output[0].Checksums())
+			if !strings.HasPrefix(sidecar, sha256Hex(content)+"  http://xyz.org/data/abc/waternetwork_25832.gml\n") {
+				t.Errorf("unexpected checksums: %s", sidecar)
+			}
+		}
+	}
+
+	// the unchanged data is downloaded once, the cache survives a save and load
+	if downloads.Load() != 1 {
+		t.Errorf("expected 1 download, got: %d", downloads.Load())
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadCache(filepath.Join(dir, "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum, ok := loaded.checksum(server.URL+"/waternetwork_25832.gml", Resource{ETag: `"v1"`}); !ok || sum != sha256Hex(content) {
+		t.Errorf("expected the cached checksum, got: %s", sum)
+	}
+	if _, ok := loaded.checksum(server.URL+"/waternetwork_25832.gml", Resource{ETag: `"v2"`}); ok {
+		t.Errorf("expected no cached checksum for a changed ETag")
+	}
+}
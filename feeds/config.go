@@ -0,0 +1,273 @@
+package feeds
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/imdario/mergo"
+	"gopkg.in/yaml.v3"
+)
+
+// envVar matches a ${NAME} environment variable in the values of the config
+var envVar = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// LoadFeeds reads the config files and merges them into one Feeds, a directory is read as all .yaml and .yml files in it,
+// the files that are included by a config file are read as well, see loader.loadFile,
+// the returned error joins an error for every feed id that is used in more than one file
+func LoadFeeds(files ...string) (Feeds, error) {
+	l := loader{read: make(map[string]bool), sources: make(map[string]string)}
+	for _, file := range files {
+		if err := l.load(file); err != nil {
+			return l.feeds, err
+		}
+	}
+	return l.feeds, errors.Join(l.errs...)
+}
+
+// loader merges config files, it remembers the files that are read and the file of every feed id
+type loader struct {
+	feeds   Feeds
+	read    map[string]bool
+	sources map[string]string
+	errs    []error
+}
+
+// load reads a config file or all config files in a directory and its subdirectories
+func (l *loader) load(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return l.loadFile(path)
+	}
+	return filepath.WalkDir(path, func(file string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ext := strings.ToLower(filepath.Ext(file)); !d.IsDir() && (ext == `.yaml` || ext == `.yml`) {
+			return l.loadFile(file)
+		}
+		return nil
+	})
+}
+
+// loadFile reads a config file and the files that match the glob patterns of its `include`,
+// relative to the directory of the config file, a file is only read once
+func (l *loader) loadFile(file string) error {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return err
+	}
+	if l.read[abs] {
+		return nil
+	}
+	l.read[abs] = true
+
+	fs, include, err := readFile(file)
+	if err != nil {
+		return err
+	}
+	if err := l.merge(file, fs); err != nil {
+		return err
+	}
+
+	for _, pattern := range include {
+		matches, err := filepath.Glob(resolvePath(pattern, filepath.Dir(file)))
+		if err != nil {
+			return fmt.Errorf("invalid include %s of %s: %w", pattern, file, err)
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("include %s of %s matches no files", pattern, file)
+		}
+		for _, match := range matches {
+			if err := l.load(match); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// merge adds the feeds of a file, the S3 config and the defaults are merged field by field, the first file that sets a field wins
+func (l *loader) merge(file string, fs Feeds) error {
+	if err := mergo.Merge(&l.feeds.S3, fs.S3); err != nil {
+		return err
+	}
+	if fs.Defaults != nil {
+		if l.feeds.Defaults == nil {
+			l.feeds.Defaults = &Feed{}
+		}
+		if err := mergo.Merge(l.feeds.Defaults, *fs.Defaults); err != nil {
+			return err
+		}
+	}
+	for _, f := range fs.Feeds {
+		if source, ok := l.sources[f.ID]; ok {
+			l.errs = append(l.errs, fmt.Errorf("duplicate feed id %s in %s and %s", f.ID, source, file))
+			continue
+		}
+		l.sources[f.ID] = file
+		l.feeds.Feeds = append(l.feeds.Feeds, f)
+	}
+	return nil
+}
+
+// readFile reads and unmarshals a single config file, relative paths in the `data` of links
+// and the `source` of generators are resolved relative to the directory of the config file,
+// the values of the config are expanded before they are unmarshalled, see expand,
+// the glob patterns of the `include` are returned
+func readFile(file string) (Feeds, []string, error) {
+	var fs Feeds
+
+	doc, err := os.ReadFile(file)
+	if err != nil {
+		return fs, nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(doc, &node); err != nil {
+		return fs, nil, fmt.Errorf("could not unmarshal %s: %w", file, err)
+	}
+	if err := expand(&node); err != nil {
+		return fs, nil, fmt.Errorf("could not expand %s: %w", file, err)
+	}
+	var include []string
+	if value := removeKey(&node, `include`); value != nil {
+		if err := value.Decode(&include); err != nil {
+			return fs, nil, fmt.Errorf("could not unmarshal the include of %s: %w", file, err)
+		}
+	}
+	if err := node.Decode(&fs); err != nil {
+		return fs, nil, fmt.Errorf("could not unmarshal %s: %w", file, err)
+	}
+
+	dir := filepath.Dir(file)
+	for _, f := range fs.Feeds {
+		for generatorIndex, g := range f.Generate {
+			f.Generate[generatorIndex].Source = resolvePath(g.Source, dir)
+		}
+		for _, entry := range f.Entry {
+			for linkIndex, link := range entry.Link {
+				if link.Data != nil {
+					data := resolvePath(*link.Data, dir)
+					entry.Link[linkIndex].Data = &data
+				}
+			}
+		}
+	}
+	return fs, include, nil
+}
+
+// expand replaces the ${NAME} environment variables in all values of the config and executes
+// the values with {{ }} as a text/template, with the `vars` section of the config as data,
+// the `vars` section itself is removed, undefined variables are errors
+func expand(doc *yaml.Node) error {
+	vars := make(map[string]any)
+	if value := removeKey(doc, `vars`); value != nil {
+		if err := scalars(value, expandEnv); err != nil {
+			return err
+		}
+		if err := value.Decode(&vars); err != nil {
+			return fmt.Errorf("line %d: invalid 'vars': %w", value.Line, err)
+		}
+	}
+
+	return scalars(doc, func(node *yaml.Node) error {
+		if err := expandEnv(node); err != nil {
+			return err
+		}
+		if !strings.Contains(node.Value, `{{`) {
+			return nil
+		}
+		t, err := template.New(``).Option(`missingkey=error`).Parse(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		var value strings.Builder
+		if err := t.Execute(&value, vars); err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		setValue(node, value.String())
+		return nil
+	})
+}
+
+// removeKey removes a key from the top level mapping of a document and returns its value, or nil
+func removeKey(doc *yaml.Node, key string) *yaml.Node {
+	root := doc
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key {
+			value := root.Content[i+1]
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
+
+// expandEnv replaces the ${NAME} environment variables in the value of a node
+func expandEnv(node *yaml.Node) error {
+	var err error
+	value := envVar.ReplaceAllStringFunc(node.Value, func(match string) string {
+		name := envVar.FindStringSubmatch(match)[1]
+		env, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("line %d: environment variable %s is not set", node.Line, name)
+		}
+		return env
+	})
+	if err != nil {
+		return err
+	}
+	if value != node.Value {
+		setValue(node, value)
+	}
+	return nil
+}
+
+// setValue sets the value of a scalar node, the type of a plain value is resolved again, so ${PORT} can be a number
+func setValue(node *yaml.Node, value string) {
+	node.Value = value
+	if node.Style == 0 {
+		node.Tag = ``
+	}
+}
+
+// scalars calls fn for all scalar nodes
+func scalars(node *yaml.Node, fn func(node *yaml.Node) error) error {
+	if node.Kind == yaml.ScalarNode {
+		return fn(node)
+	}
+	for _, child := range node.Content {
+		if err := scalars(child, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateYAML encodes the Feeds as a config file
+func (fs Feeds) GenerateYAML() ([]byte, error) {
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(fs); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
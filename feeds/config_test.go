@@ -0,0 +1,220 @@
+package feeds
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadFeedsExpand(t *testing.T) {
+	t.Setenv("ATOM_BASE_URL", "http://test.xyz.org")
+	t.Setenv("ATOM_LENGTH", "34987")
+
+	config := `vars:
+  base: "${ATOM_BASE_URL}/download"
+  rights: "Copyright (c) 2012, XYZ; all rights reserved"
+feeds:
+ - id: "{{ .base }}/en.xml"
+   title: 'Download service, not {{ "{{" }} templated'
+   rights: "{{ .rights }}"
+   entry:
+    - id: "{{ .base }}/waternetwork_25832.gml"
+      rights: '{{ .rights | printf "%s." }}'
+      link:
+       - href: "${ATOM_BASE_URL}/data/waternetwork_25832.gml"
+         length: ${ATOM_LENGTH}
+`
+	filename := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(filename, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fs, err := LoadFeeds(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := fs.Feeds[0]
+	if f.ID != "http://test.xyz.org/download/en.xml" || f.Title != "Download service, not {{ templated" || f.Rights != "Copyright (c) 2012, XYZ; all rights reserved" {
+		t.Errorf("unexpected feed: %+v", f)
+	}
+	entry := f.Entry[0]
+	if entry.ID != "http://test.xyz.org/download/waternetwork_25832.gml" || entry.Rights != "Copyright (c) 2012, XYZ; all rights reserved." {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Link[0].Href != "http://test.xyz.org/data/waternetwork_25832.gml" || entry.Link[0].Length != "34987" {
+		t.Errorf("unexpected link: %+v", entry.Link[0])
+	}
+}
+
+func TestLoadFeedsExpandErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name:     "undefined environment variable",
+			config:   "feeds:\n - id: \"${ATOM_UNDEFINED}/en.xml\"\n",
+			expected: "line 2: environment variable ATOM_UNDEFINED is not set",
+		},
+		{
+			name:     "undefined environment variable in vars",
+			config:   "vars:\n  base: \"${ATOM_UNDEFINED}\"\nfeeds: []\n",
+			expected: "line 2: environment variable ATOM_UNDEFINED is not set",
+		},
+		{
+			name:     "undefined variable",
+			config:   "vars:\n  base: \"http://xyz.org\"\nfeeds:\n - id: \"{{ .host }}/en.xml\"\n",
+			expected: `line 4: template: :1:3: executing "" at <.host>: map has no entry for key "host"`,
+		},
+		{
+			name:     "invalid template",
+			config:   "feeds:\n - id: \"{{ .base \"\n",
+			expected: "line 2: template: :1: unclosed action",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(filename, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadFeeds(filename)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %s, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestFeedsGenerateYAML(t *testing.T) {
+	fs := Feeds{Feeds: []Feed{{
+		ID:     "http://xyz.org/data/abc/waternetwork.xml",
+		Lang:   sp("en"),
+		Rights: "Copyright XYZ",
+		Entry:  []Entry{{ID: "http://xyz.org/data/abc/waternetwork_25832.gml", Link: []Link{{Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Data: sp("./data/waternetwork_25832.gml")}}}},
+	}}}
+	b, err := fs.GenerateYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Feeds
+	if err := yaml.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Feeds[0].Rights != "Copyright XYZ" || *decoded.Feeds[0].Entry[0].Link[0].Data != "./data/waternetwork_25832.gml" {
+		t.Errorf("unexpected decoded feed %+v from:\n%s", decoded.Feeds[0], b)
+	}
+	if strings.Contains(string(b), "xmlname") || strings.Contains(string(b), "stylesheet") {
+		t.Errorf("unexpected field in:\n%s", b)
+	}
+}
+
+func TestLoadFeedsFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"service.yaml": `include:
+ - "datasets/*.yaml"
+ - "service.yaml"
+s3:
+  endpoint: "s3.xyz.org"
+feeds:
+ - id: "http://xyz.org/download/en.xml"
+   entry:
+    - id: "http://xyz.org/data/abc/waternetwork.xml"
+      link:
+       - rel: alternate
+         href: "http://xyz.org/data/abc/waternetwork.xml"
+         type: "application/atom+xml"
+`,
+		"datasets/abc.yaml": `s3:
+  endpoint: "s3.abc.org"
+  region: "eu-central-1"
+defaults:
+  rights: "Copyright XYZ"
+feeds:
+ - id: "http://xyz.org/data/abc/waternetwork.xml"
+   entry:
+    - id: "http://xyz.org/data/abc/waternetwork_25832.gml"
+      updated: "2014-06-15T11:12:34Z"
+      link:
+       - href: "http://xyz.org/data/abc/waternetwork_25832.gml"
+         data: "waternetwork_25832.gml"
+`,
+		"datasets/def/def.yml": `feeds:
+ - id: "http://xyz.org/data/def/waternetwork.xml"
+`,
+	}
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs, err := LoadFeeds(filepath.Join(dir, "service.yaml"), filepath.Join(dir, "datasets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, f := range fs.Feeds {
+		ids = append(ids, f.ID)
+	}
+	expected := []string{"http://xyz.org/download/en.xml", "http://xyz.org/data/abc/waternetwork.xml", "http://xyz.org/data/def/waternetwork.xml"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected feeds %v, got %v", expected, ids)
+	}
+	if fs.S3.Endpoint != "s3.xyz.org" || fs.S3.Region != "eu-central-1" || fs.Defaults == nil || fs.Defaults.Rights != "Copyright XYZ" {
+		t.Errorf("unexpected merged s3 and defaults: %+v %+v", fs.S3, fs.Defaults)
+	}
+	if data := *fs.Feeds[1].Entry[0].Link[0].Data; data != filepath.Join(dir, "datasets", "waternetwork_25832.gml") {
+		t.Errorf("expected the data path to be relative to the included file, got: %s", data)
+	}
+
+	// the updated of the service feed entry comes from the dataset feed in another file
+	processed, err := ProcessFeeds(fs, Options{SkipData: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated := processed[0].Entry[0].Updated; updated == nil || *updated != "2014-06-15T11:12:34Z" {
+		t.Errorf("expected the updated of the dataset feed, got %v", updated)
+	}
+}
+
+func TestLoadFeedsFilesErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yaml":       "feeds:\n - id: \"http://xyz.org/a.xml\"\n - id: \"http://xyz.org/b.xml\"\n",
+		"b.yaml":       "feeds:\n - id: \"http://xyz.org/b.xml\"\n",
+		"include.yaml": "include:\n - \"missing/*.yaml\"\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs, err := LoadFeeds(filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml"))
+	expected := "duplicate feed id http://xyz.org/b.xml in " + filepath.Join(dir, "a.yaml") + " and " + filepath.Join(dir, "b.yaml")
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %s, got %v", expected, err)
+	}
+	if len(fs.Feeds) != 2 {
+		t.Errorf("expected the duplicate to be left out, got %d feeds", len(fs.Feeds))
+	}
+
+	_, err = LoadFeeds(filepath.Join(dir, "include.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "include missing/*.yaml of "+filepath.Join(dir, "include.yaml")+" matches no files") {
+		t.Errorf("expected an include error, got %v", err)
+	}
+
+	if _, err := LoadFeeds(filepath.Join(dir, "missing.yaml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
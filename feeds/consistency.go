@@ -0,0 +1,128 @@
+package feeds
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Consistent validates the references between the Feeds
+// INSPIRE requires that every service feed entry points to an existing dataset feed through an 'alternate' link
+// and that the 'up' link of that dataset feed points back to the service feed.
+// Feeds are resolved by their 'id' and by the href of their 'self' links.
+//
+//nolint:cyclop
+func (fs Feeds) Consistent() Report {
+	var report Report
+
+	index := fs.index()
+	parents := make(map[int][]int)        // dataset feed index -> service feed indexes
+	namespaces := make(map[string]string) // spatial_dataset_identifier_code -> namespace
+	hasServiceFeed := false
+
+	for serviceIndex, service := range fs.Feeds {
+		if service.Kind() != ServiceFeed {
+			continue
+		}
+		hasServiceFeed = true
+
+		for entryIndex, entry := range service.Entry {
+			entryPath := fmt.Sprintf("feeds[%d].entry[%d]", serviceIndex, entryIndex)
+
+			if entry.SpatialDatasetIdentifierCode != nil && entry.SpatialDatasetIdentifierNamespace != nil {
+				code, namespace := *entry.SpatialDatasetIdentifierCode, *entry.SpatialDatasetIdentifierNamespace
+				if known, ok := namespaces[code]; ok && known != namespace {
+					report.Error(`TG Requirement 15`, entryPath+`.spatial_dataset_identifier_namespace`, invalididentifierpair)
+				} else {
+					namespaces[code] = namespace
+				}
+			}
+
+			for linkIndex, link := range entry.Link {
+				if !isFeedLink(link) {
+					continue
+				}
+				datasetIndex, ok := index[link.Href]
+				if !ok || fs.Feeds[datasetIndex].Kind() != DatasetFeed {
+					report.Error(`TG Requirement 16`, fmt.Sprintf("%s.link[%d].href", entryPath, linkIndex), invaliddatasetfeedlink)
+					continue
+				}
+				parents[datasetIndex] = append(parents[datasetIndex], serviceIndex)
+				report.Merge(entryPath, matchIdentifiers(entry, fs.Feeds[datasetIndex]))
+			}
+		}
+	}
+
+	for datasetIndex, dataset := range fs.Feeds {
+		if dataset.Kind() != DatasetFeed {
+			continue
+		}
+		feedPath := fmt.Sprintf("feeds[%d]", datasetIndex)
+		if _, ok := parents[datasetIndex]; !ok {
+			if hasServiceFeed {
+				report.Warning(`TG Requirement 16`, feedPath, warningunreferenceddataset)
+			}
+			continue
+		}
+
+		for linkIndex, link := range dataset.Link {
+			if link.Rel != up {
+				continue
+			}
+			serviceIndex, ok := index[link.Href]
+			if !ok || !slices.Contains(parents[datasetIndex], serviceIndex) {
+				report.Warning(`TG Recommendation 9`, fmt.Sprintf("%s.link[%d].href", feedPath, linkIndex), warningupservicefeedlink)
+			}
+		}
+		if dataset.Up != nil {
+			serviceIndex, ok := index[dataset.Up.Href]
+			if !ok || !slices.Contains(parents[datasetIndex], serviceIndex) {
+				report.Warning(`TG Recommendation 9`, feedPath+`.up.href`, warningupservicefeedlink)
+			}
+		}
+	}
+
+	return report
+}
+
+// index maps the id and self hrefs of the Feeds to their position
+func (fs Feeds) index() map[string]int {
+	index := make(map[string]int)
+	for i, feed := range fs.Feeds {
+		for _, l := range feed.Links(self) {
+			if _, ok := index[l.Href]; !ok {
+				index[l.Href] = i
+			}
+		}
+	}
+	// an id always takes precedence over a self href
+	for i, feed := range fs.Feeds {
+		index[feed.ID] = i
+	}
+	return index
+}
+
+// matchIdentifiers reports dataset feed entries that use a different spatial_dataset_identifier
+// than the service feed entry that references the dataset feed
+func matchIdentifiers(entry Entry, dataset Feed) Report {
+	var report Report
+	if entry.SpatialDatasetIdentifierCode == nil || entry.SpatialDatasetIdentifierNamespace == nil {
+		return report
+	}
+	for _, datasetEntry := range dataset.Entry {
+		if datasetEntry.SpatialDatasetIdentifierCode == nil || datasetEntry.SpatialDatasetIdentifierNamespace == nil {
+			continue
+		}
+		if *datasetEntry.SpatialDatasetIdentifierCode != *entry.SpatialDatasetIdentifierCode ||
+			*datasetEntry.SpatialDatasetIdentifierNamespace != *entry.SpatialDatasetIdentifierNamespace {
+			report.Error(`TG Requirement 15`, `spatial_dataset_identifier_code`, invaliddatasetidentifier)
+			return report
+		}
+	}
+	return report
+}
+
+// isFeedLink returns true when the link is an 'alternate' link to an ATOM feed
+func isFeedLink(l Link) bool {
+	return l.Rel == alternate && strings.HasPrefix(l.Type, `application/atom+xml`)
+}
@@ -0,0 +1,99 @@
+package feeds
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConsistent(t *testing.T) {
+	service := func(entries ...Entry) Feed {
+		return Feed{
+			ID:    "http://xyz.org/download/en.xml",
+			Link:  []Link{{Rel: search, Href: "http://xyz.org/search/opensearchdescription.xml"}},
+			Entry: entries,
+		}
+	}
+	entry := func(code, namespace, href string) Entry {
+		return Entry{
+			ID:                                "http://xyz.org/data/abc/waternetwork.xml",
+			SpatialDatasetIdentifierCode:      sp(code),
+			SpatialDatasetIdentifierNamespace: sp(namespace),
+			Link:                              []Link{{Rel: alternate, Type: "application/atom+xml", Href: href}},
+		}
+	}
+	dataset := func(id, upHref string, entries ...Entry) Feed {
+		return Feed{
+			ID:    id,
+			Self:  &Link{Href: id + "?self"},
+			Link:  []Link{{Rel: up, Href: upHref}},
+			Entry: entries,
+		}
+	}
+
+	var tests = []struct {
+		input    Feeds
+		expected []Finding
+	}{
+		0: {
+			input: Feeds{Feeds: []Feed{
+				service(entry("wn_id1", "http://xyz.org/", "http://xyz.org/data/abc/waternetwork.xml")),
+				dataset("http://xyz.org/data/abc/waternetwork.xml", "http://xyz.org/download/en.xml"),
+			}},
+			expected: nil,
+		},
+		1: {
+			input: Feeds{Feeds: []Feed{
+				service(entry("wn_id1", "http://xyz.org/", "http://xyz.org/data/abc/waternetwork.xml?self")),
+				dataset("http://xyz.org/data/abc/waternetwork.xml", "http://xyz.org/download/en.xml"),
+			}},
+			expected: nil,
+		},
+		2: {
+			input: Feeds{Feeds: []Feed{
+				service(entry("wn_id1", "http://xyz.org/", "http://xyz.org/data/waternetwork_feed.xml")),
+				dataset("http://xyz.org/data/abc/waternetwork.xml", "http://xyz.org/download/en.xml"),
+			}},
+			expected: []Finding{
+				{Severity: SeverityError, Requirement: `TG Requirement 16`, Path: `feeds[0].entry[0].link[0].href`, Message: invaliddatasetfeedlink},
+				{Severity: SeverityWarning, Requirement: `TG Requirement 16`, Path: `feeds[1]`, Message: warningunreferenceddataset},
+			},
+		},
+		3: {
+			input: Feeds{Feeds: []Feed{
+				service(entry("wn_id1", "http://xyz.org/", "http://xyz.org/data/abc/waternetwork.xml")),
+				dataset("http://xyz.org/data/abc/waternetwork.xml", "http://xyz.org/download/de.xml"),
+			}},
+			expected: []Finding{
+				{Severity: SeverityWarning, Requirement: `TG Recommendation 9`, Path: `feeds[1].link[0].href`, Message: warningupservicefeedlink},
+			},
+		},
+		4: {
+			input: Feeds{Feeds: []Feed{
+				service(
+					entry("wn_id1", "http://xyz.org/", "http://xyz.org/data/abc/waternetwork.xml"),
+					entry("wn_id1", "http://abc.org/", "http://xyz.org/data/abc/waternetwork.xml"),
+				),
+				dataset("http://xyz.org/data/abc/waternetwork.xml", "http://xyz.org/download/en.xml",
+					Entry{SpatialDatasetIdentifierCode: sp("wn_id2"), SpatialDatasetIdentifierNamespace: sp("http://xyz.org/")}),
+			}},
+			expected: []Finding{
+				{Severity: SeverityError, Requirement: `TG Requirement 15`, Path: `feeds[0].entry[0].spatial_dataset_identifier_code`, Message: invaliddatasetidentifier},
+				{Severity: SeverityError, Requirement: `TG Requirement 15`, Path: `feeds[0].entry[1].spatial_dataset_identifier_namespace`, Message: invalididentifierpair},
+				{Severity: SeverityError, Requirement: `TG Requirement 15`, Path: `feeds[0].entry[1].spatial_dataset_identifier_code`, Message: invaliddatasetidentifier},
+			},
+		},
+		5: {
+			input: Feeds{Feeds: []Feed{
+				dataset("http://xyz.org/data/abc/waternetwork.xml", "http://xyz.org/download/en.xml"),
+			}},
+			expected: nil,
+		},
+	}
+
+	for k, test := range tests {
+		report := test.input.Consistent()
+		if !reflect.DeepEqual(report.Findings, test.expected) {
+			t.Errorf("test: %d, expected: %v \ngot: %v", k, test.expected, report.Findings)
+		}
+	}
+}
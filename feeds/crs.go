@@ -0,0 +1,58 @@
+package feeds
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// epsgCSV contains the code and official name of the EPSG CRSs that can be used in the `crs` shorthand,
+// these are the ETRS89 based CRSs of INSPIRE, WGS 84 and its UTM zones and common national CRSs
+//
+//go:embed epsg.csv
+var epsgCSV string
+
+// epsg maps the EPSG codes to their official names
+var epsg = parseEPSG(epsgCSV)
+
+func parseEPSG(doc string) map[int]string {
+	records, err := csv.NewReader(strings.NewReader(doc)).ReadAll()
+	if err != nil {
+		panic(err)
+	}
+	names := make(map[int]string, len(records))
+	for _, record := range records[1:] {
+		code, err := strconv.Atoi(record[0])
+		if err != nil {
+			panic(err)
+		}
+		names[code] = record[1]
+	}
+	return names
+}
+
+// EPSGCategory returns the CRS Category of an EPSG code, with the OGC URI as term and the official name as label
+// it returns false when the code is not in the embedded registry
+func EPSGCategory(code int) (Category, bool) {
+	name, ok := epsg[code]
+	if !ok {
+		return Category{}, false
+	}
+	return Category{Term: `http://www.opengis.net/def/crs/EPSG/0/` + strconv.Itoa(code), Label: name}, true
+}
+
+// withCRS returns the categories of the entry completed with the categories of its known `crs` codes,
+// a code that already has a category is not added again
+func (e Entry) withCRS() []Category {
+	categories := slices.Clone(e.Category)
+	for _, code := range e.CRS {
+		category, ok := EPSGCategory(code)
+		if !ok || slices.ContainsFunc(categories, func(c Category) bool { return c.Term == category.Term }) {
+			continue
+		}
+		categories = append(categories, category)
+	}
+	return categories
+}
@@ -0,0 +1,39 @@
+package feeds
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntryWithCRS(t *testing.T) {
+	var tests = []struct {
+		input    Entry
+		expected []Category
+	}{
+		0: {input: Entry{CRS: []int{25832, 4258}}, expected: []Category{
+			{Term: "http://www.opengis.net/def/crs/EPSG/0/25832", Label: "ETRS89 / UTM zone 32N"},
+			{Term: "http://www.opengis.net/def/crs/EPSG/0/4258", Label: "ETRS89"},
+		}},
+		// a configured category takes precedence, unknown codes are left to the validation
+		1: {input: Entry{
+			Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/28992", Label: "RD New"}},
+			CRS:      []int{28992, 3035, 99999},
+		}, expected: []Category{
+			{Term: "http://www.opengis.net/def/crs/EPSG/0/28992", Label: "RD New"},
+			{Term: "http://www.opengis.net/def/crs/EPSG/0/3035", Label: "ETRS89-extended / LAEA Europe"},
+		}},
+		2: {input: Entry{Category: []Category{{Term: "Hydrography", Label: "Hydrography"}}}, expected: []Category{
+			{Term: "Hydrography", Label: "Hydrography"},
+		}},
+		// the current EPSG name, not the deprecated ETRS89 / TM32
+		3: {input: Entry{CRS: []int{3044}}, expected: []Category{
+			{Term: "http://www.opengis.net/def/crs/EPSG/0/3044", Label: "ETRS89 / UTM zone 32N (N-E)"},
+		}},
+	}
+
+	for k, test := range tests {
+		if got := test.input.withCRS(); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test: %d, expected: %+v \ngot: %+v", k, test.expected, got)
+		}
+	}
+}
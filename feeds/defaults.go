@@ -1,10 +1,20 @@
 package feeds
 
+import (
+	"fmt"
+	"slices"
+
+	"github.com/imdario/mergo"
+	"gopkg.in/yaml.v3"
+)
+
 const (
 	describedby = `describedby`
 	self        = `self`
 	search      = `search`
 	up          = `up`
+	alternate   = `alternate`
+	section     = `section`
 
 	// DEFAULTLANG contains the default language of the ATOM Feed
 	// The default of the DEFAULTLANG is 'en'
@@ -19,12 +29,39 @@ const (
 	invaliddatetime = "invalid 'updated', needs to be a valid datetime with timezone see TG Requirement 11"
 	invalidauthor   = "invalid 'author', cannot be empty see TG Requirement 12"
 	invalidupdated  = "invalid 'updated', updated is required see TG Requirements 11"
-	invalidlinktime = "invalid 'link.time', needs to be a valid datetime with timezone see TG Recommendation 11"
-	invalidlinkbbox = "invalid 'link.bbox', needs to be a valid georss:bbox see TG Recommendation 10"
+
+	invalidgeorss          = "invalid '%s' of entry %s, needs to be a list of latitude longitude pairs see TG Requirement 17"
+	invalidgeorssodd       = "invalid '%s' of entry %s, needs an even number of coordinates see TG Requirement 17"
+	invalidgeorsspositions = "invalid '%s' of entry %s, needs %s position(s) see TG Requirement 17"
+	invalidgeorssrange     = "invalid '%s' of entry %s, latitudes need to be within [-90, 90] and longitudes within [-180, 180] see TG Requirement 17"
+	invalidgeorsscorners   = "invalid '%s' of entry %s, the lower corner cannot be above the upper corner see TG Requirement 17"
+	invalidpolygonclosed   = "invalid 'polygon' of entry %s, the last position needs to equal the first to close the ring see TG Requirement 17"
+	invalidwheresrsname    = "invalid 'where' of entry %s, the gml:Envelope needs a srsName see TG Requirement 17"
+	invalidwherecorners    = "invalid 'where' of entry %s, the corners of the gml:Envelope need the same number of coordinates see TG Requirement 17"
+
+	invalidself              = "invalid 'link', a feed needs a 'self' link see TG Requirement 7"
+	invaliddescribedby       = "invalid 'link', a service feed needs a 'describedby' link to the metadata of the download service see TG Requirement 6"
+	invalidsearch            = "invalid 'link', a service feed needs a 'search' link to the OpenSearch description see TG Requirement 8"
+	invalididentifiercode    = "invalid 'spatial_dataset_identifier_code', cannot be empty for a service feed entry see TG Requirement 15"
+	invalididentifierns      = "invalid 'spatial_dataset_identifier_namespace', cannot be empty for a service feed entry see TG Requirement 15"
+	invalidentryfeedlink     = "invalid 'link', a service feed entry needs an 'alternate' link to a dataset feed see TG Requirement 16"
+	invalidentrycrs          = "invalid 'category', a dataset feed entry needs at least one CRS category see TG Requirement 22"
+	invalidentryepsg         = "invalid 'crs', unknown EPSG code see TG Requirement 22"
+	invalidlinktype          = "invalid 'link.type', cannot be empty for a download link see TG Requirement 20"
+	invalidlinkhreflang      = "invalid 'link.hreflang', cannot be empty for a download link see TG Requirement 21"
+	invaliddatasetfeedlink   = "invalid 'link.href', the 'alternate' link does not point to an existing dataset feed see TG Requirement 16"
+	invalididentifierpair    = "invalid 'spatial_dataset_identifier_namespace', differs from the one used for the same 'spatial_dataset_identifier_code' see TG Requirement 15"
+	invaliddatasetidentifier = "invalid 'spatial_dataset_identifier_code', differs from the one used in the referenced dataset feed see TG Requirement 15"
 )
 
 const (
-	warningsubtitle = "missing 'subtitle' may be a human readable subtitle for the feed see TG Recommendation 1"
+	warningsubtitle            = "missing 'subtitle' may be a human readable subtitle for the feed see TG Recommendation 1"
+	warningunreferenceddataset = "dataset feed is not referenced by an 'alternate' link of any service feed entry see TG Requirement 16"
+	warninglinktime            = "invalid 'link.time', should be a valid datetime with timezone see TG Recommendation 11"
+	warninglinkbbox            = "invalid 'link.bbox', should be a valid georss:bbox see TG Recommendation 10"
+	warningupservicefeedlink   = "invalid 'link.href', the 'up' link should point to a service feed referencing this dataset feed see TG Recommendation 9"
+	warningnoextent            = "missing 'link.bbox', the extent of the data could not be read: %v see TG Recommendation 10"
+	warninggeorssswapped       = "'%s' of entry %s looks like longitude latitude pairs, georss has the latitude first see TG Requirement 17"
 )
 
 // GetDefaultFeedProperties returns mandatory/static ServiceFeed properties
@@ -42,6 +79,75 @@ func GetDefaultFeedProperties() Feed {
 	return f
 }
 
+// the fields of a feed that identify that feed, or its kind, and are never taken from the Defaults,
+// the 'updated' of a feed is derived from its entries
+var feedOnlyFields = []string{`id`, self, describedby, search, up, `link`, `updated`, `entry`, `generate`}
+
+// checkDefaults returns an error for a field of the defaults node that is in feedOnlyFields
+// or a text in multiple languages, the defaults are shared by the variants of all languages
+func checkDefaults(defaults *yaml.Node) error {
+	for _, key := range feedOnlyFields {
+		if value := mappingValue(defaults, key); value != nil {
+			return fmt.Errorf("line %d: the defaults can not have a '%s', it only applies to a single feed", value.Line, key)
+		}
+	}
+	return texts(defaults, func(key string, text *yaml.Node) error {
+		if text.Kind == yaml.MappingNode {
+			return fmt.Errorf("line %d: '%s' of the defaults can not have texts in multiple languages, set it in the feeds instead", text.Line, key)
+		}
+		return nil
+	})
+}
+
+// withDefaults fills the empty fields of the Feed, its entries and their links, the precedence is:
+//   - the values of the Feed
+//   - the Defaults of the Feeds, except the feedOnlyFields
+//   - GetDefaultFeedProperties
+//
+// after which entries without 'rights' get the 'rights' of the Feed and their links without 'hreflang' the 'lang' of the Feed
+func (f *Feed) withDefaults(defaults *Feed) error {
+	if defaults != nil {
+		d := *defaults
+		d.ID, d.Self, d.Describedby, d.Search, d.Up, d.Link = ``, nil, nil, nil, nil, nil
+		d.Updated, d.Entry, d.Generate = nil, nil, nil
+		if err := mergo.Merge(f, d); err != nil {
+			return err
+		}
+	}
+	if err := mergo.Merge(f, GetDefaultFeedProperties()); err != nil {
+		return err
+	}
+
+	for entryIndex, entry := range f.Entry {
+		if entry.Rights == `` {
+			f.Entry[entryIndex].Rights = f.Rights
+		}
+		for linkIndex, link := range entry.Link {
+			entry.Link[linkIndex] = link.SetHrefLang(*f.Lang)
+		}
+	}
+	return nil
+}
+
+// Resolved returns a copy of the Feeds with the Defaults applied to every feed, see Feed.withDefaults,
+// the `data` and the generators are not resolved
+func (fs Feeds) Resolved() (Feeds, error) {
+	feeds := make([]Feed, 0, len(fs.Feeds))
+	for _, f := range fs.Feeds {
+		f.Entry = slices.Clone(f.Entry)
+		for entryIndex, entry := range f.Entry {
+			f.Entry[entryIndex].Link = slices.Clone(entry.Link)
+		}
+		if err := f.withDefaults(fs.Defaults); err != nil {
+			return fs, &ProcessError{FeedID: f.ID, Err: err}
+		}
+		feeds = append(feeds, f)
+	}
+	fs.Feeds = feeds
+	fs.Defaults = nil
+	return fs, nil
+}
+
 // DescribedBy returns a Link containing a mandatory DescribedBy element
 // TG Requirement 6 - Technical Guidance Download Services v3.1
 func DescribedBy(l Link) Link {
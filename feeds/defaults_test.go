@@ -0,0 +1,150 @@
+package feeds
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFeedsResolved(t *testing.T) {
+	fs := Feeds{
+		Defaults: &Feed{
+			ID:      "http://xyz.org/defaults.xml",
+			Lang:    sp("nl"),
+			Rights:  "Copyright XYZ",
+			Author:  Author{Name: "John Doe", Email: "doe@xyz.org"},
+			Up:      &Link{Href: "http://xyz.org/download/en.xml"},
+			Search:  &Link{Href: "http://xyz.org/search/opensearchdescription.xml"},
+			Updated: sp("2012-03-31T13:45:03Z"),
+			Self:    &Link{Href: "http://xyz.org/defaults.xml"},
+			Link:    []Link{{Href: "http://xyz.org/defaults.html", Rel: "alternate"}},
+			Entry:   []Entry{{ID: "http://xyz.org/defaults.gml"}},
+		},
+		Feeds: []Feed{
+			{
+				ID: "http://xyz.org/data/abc/waternetwork.xml",
+				Entry: []Entry{
+					{ID: "http://xyz.org/data/abc/waternetwork_25832.gml", Link: []Link{{Href: "http://xyz.org/data/abc/waternetwork_25832.gml"}}},
+					{ID: "http://xyz.org/data/abc/waternetwork_4258.gml", Rights: "Copyright ABC", Link: []Link{{Href: "http://xyz.org/data/abc/waternetwork_4258.gml", Hreflang: sp("en")}}},
+				},
+			},
+			{
+				ID:     "http://xyz.org/data/def/waternetwork.xml",
+				Lang:   sp("en"),
+				Rights: "Copyright DEF",
+				Author: Author{Name: "Jane Doe"},
+			},
+		},
+	}
+
+	resolved, err := fs.Resolved()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved.Defaults != nil {
+		t.Errorf("expected the defaults to be applied, got %+v", resolved.Defaults)
+	}
+
+	abc := resolved.Feeds[0]
+	if abc.ID != "http://xyz.org/data/abc/waternetwork.xml" || *abc.Lang != "nl" || abc.Rights != "Copyright XYZ" || abc.Author.Name != "John Doe" || abc.Up != nil || abc.Search != nil || abc.Updated != nil || abc.Self != nil || len(abc.Link) != 0 || len(abc.Entry) != 2 {
+		t.Errorf("unexpected feed with defaults: %+v", abc)
+	}
+	if abc.Xmlns != "http://www.w3.org/2005/Atom" {
+		t.Errorf("expected the default feed properties, got %+v", abc)
+	}
+	expected := []Entry{
+		{ID: "http://xyz.org/data/abc/waternetwork_25832.gml", Rights: "Copyright XYZ", Link: []Link{{Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Hreflang: sp("nl")}}},
+		{ID: "http://xyz.org/data/abc/waternetwork_4258.gml", Rights: "Copyright ABC", Link: []Link{{Href: "http://xyz.org/data/abc/waternetwork_4258.gml", Hreflang: sp("en")}}},
+	}
+	if !reflect.DeepEqual(abc.Entry, expected) {
+		t.Errorf("expected entries %+v, got %+v", expected, abc.Entry)
+	}
+
+	// the values of a feed take precedence, also over the fields of a struct in the defaults
+	def := resolved.Feeds[1]
+	if *def.Lang != "en" || def.Rights != "Copyright DEF" || def.Author != (Author{Name: "Jane Doe", Email: "doe@xyz.org"}) || len(def.Entry) != 0 {
+		t.Errorf("unexpected feed with own values: %+v", def)
+	}
+
+	// the given Feeds are not changed
+	if fs.Feeds[0].Entry[0].Rights != "" || fs.Feeds[0].Entry[0].Link[0].Hreflang != nil || fs.Feeds[0].Lang != nil {
+		t.Errorf("expected the given feeds to be unchanged, got %+v", fs.Feeds[0])
+	}
+}
+
+func TestFeedsUnmarshalYAMLDefaultsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name: "self",
+			config: `
+defaults:
+  rights: "Copyright XYZ"
+  self:
+    href: "http://xyz.org/download/en.xml"
+`,
+			expected: "line 5: the defaults can not have a 'self', it only applies to a single feed",
+		},
+		{
+			name: "link",
+			config: `
+defaults:
+  link:
+    - href: "http://xyz.org/download/en.html"
+`,
+			expected: "line 4: the defaults can not have a 'link', it only applies to a single feed",
+		},
+		{
+			name: "search",
+			config: `
+defaults:
+  search:
+    href: "http://xyz.org/search/opensearchdescription.xml"
+`,
+			expected: "line 4: the defaults can not have a 'search', it only applies to a single feed",
+		},
+		{
+			name: "updated",
+			config: `
+defaults:
+  updated: "2012-03-31T13:45:03Z"
+`,
+			expected: "line 3: the defaults can not have a 'updated', it only applies to a single feed",
+		},
+		{
+			name: "rights in multiple languages",
+			config: `
+defaults:
+  rights:
+    en: "Copyright XYZ"
+    de: "Urheberrecht XYZ"
+`,
+			expected: "line 4: 'rights' of the defaults can not have texts in multiple languages, set it in the feeds instead",
+		},
+		{
+			name: "subtitle in multiple languages",
+			config: `
+defaults:
+  lang: en
+  subtitle:
+    en: "Download service"
+    de: "Downloaddienst"
+`,
+			expected: "line 5: 'subtitle' of the defaults can not have texts in multiple languages, set it in the feeds instead",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fs Feeds
+			err := yaml.Unmarshal([]byte(tt.config), &fs)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %s, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package feeds
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// extent is a bounding box in WGS 84 latitude and longitude, for ETRS89 the difference is negligible
+type extent struct {
+	minLat, minLon, maxLat, maxLon float64
+}
+
+// box returns the extent in the georss:box structure: lower latitude, lower longitude, upper latitude, upper longitude
+func (e extent) box() string {
+	return strings.Join([]string{coordinate(e.minLat), coordinate(e.minLon), coordinate(e.maxLat), coordinate(e.maxLon)}, ` `)
+}
+
+// polygon returns the extent as a closed georss:polygon ring of latitude longitude pairs
+func (e extent) polygon() string {
+	minLat, minLon, maxLat, maxLon := coordinate(e.minLat), coordinate(e.minLon), coordinate(e.maxLat), coordinate(e.maxLon)
+	return strings.Join([]string{minLat, minLon, maxLat, minLon, maxLat, maxLon, minLat, maxLon, minLat, minLon}, ` `)
+}
+
+// union returns the extent that contains both extents
+func (e extent) union(other extent) extent {
+	return extent{
+		minLat: math.Min(e.minLat, other.minLat),
+		minLon: math.Min(e.minLon, other.minLon),
+		maxLat: math.Max(e.maxLat, other.maxLat),
+		maxLon: math.Max(e.maxLon, other.maxLon),
+	}
+}
+
+// coordinate formats a latitude or longitude with 6 decimals, which is about 0.1 metre
+func coordinate(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1e6)/1e6, 'f', -1, 64)
+}
+
+// bounds is an envelope in the axis order of its CRS
+type bounds struct {
+	min, max [2]float64
+}
+
+func newBounds() *bounds {
+	return &bounds{min: [2]float64{math.Inf(1), math.Inf(1)}, max: [2]float64{math.Inf(-1), math.Inf(-1)}}
+}
+
+func (b *bounds) add(first, second float64) {
+	b.min = [2]float64{math.Min(b.min[0], first), math.Min(b.min[1], second)}
+	b.max = [2]float64{math.Max(b.max[0], first), math.Max(b.max[1], second)}
+}
+
+func (b *bounds) empty() bool {
+	return b.min[0] > b.max[0] || b.min[1] > b.max[1]
+}
+
+// toExtent transforms the bounds to an extent, the edges are densified because they are curved in latitude and longitude
+func (b *bounds) toExtent(crs crsDefinition) extent {
+	const steps = 10
+	e := extent{minLat: math.Inf(1), minLon: math.Inf(1), maxLat: math.Inf(-1), maxLon: math.Inf(-1)}
+	add := func(first, second float64) {
+		lat, lon := crs.toLatLon(first, second)
+		e = e.union(extent{minLat: lat, minLon: lon, maxLat: lat, maxLon: lon})
+	}
+	for i := range steps + 1 {
+		first := b.min[0] + (b.max[0]-b.min[0])*float64(i)/steps
+		second := b.min[1] + (b.max[1]-b.min[1])*float64(i)/steps
+		add(first, b.min[1])
+		add(first, b.max[1])
+		add(b.min[0], second)
+		add(b.max[0], second)
+	}
+	return e
+}
+
+// errUnsupportedCRS is returned for data in a CRS that can not be transformed to latitude and longitude,
+// such data gets no extent, which is reported as a warning
+var errUnsupportedCRS = errors.New(`unsupported CRS`)
+
+// epsgCode matches the EPSG code at the end of the common forms of a srsName
+var epsgCode = regexp.MustCompile(`(?i)^(?:EPSG:|urn:(?:x-)?ogc:def:crs:EPSG:[\d.]*:|https?://www\.opengis\.net/def/crs/EPSG/[\d.]+/|https?://www\.opengis\.net/gml/srs/epsg\.xml#)(\d+)$`)
+
+// srsDefinition returns the definition of the CRS of a srsName, the legacy forms EPSG:4258 and
+// http://www.opengis.net/gml/srs/epsg.xml#4258 have the longitude or easting first, like CRS84
+func srsDefinition(srsName string) (crsDefinition, error) {
+	name := strings.TrimSpace(srsName)
+	switch strings.ToUpper(name) {
+	case `CRS:84`, `URN:OGC:DEF:CRS:OGC:1.3:CRS84`, `URN:OGC:DEF:CRS:OGC::CRS84`,
+		`HTTP://WWW.OPENGIS.NET/DEF/CRS/OGC/1.3/CRS84`, `HTTP://WWW.OPENGIS.NET/DEF/CRS/OGC/0/CRS84`:
+		return crsDefinition{ellipsoid: wgs84}, nil
+	}
+
+	match := epsgCode.FindStringSubmatch(name)
+	if match == nil {
+		return crsDefinition{}, fmt.Errorf("%w: srsName %q", errUnsupportedCRS, srsName)
+	}
+	code, _ := strconv.Atoi(match[1])
+	def, err := epsgDefinition(code)
+	if err != nil {
+		return def, err
+	}
+	if strings.HasPrefix(strings.ToUpper(name), `EPSG:`) || strings.Contains(name, `epsg.xml#`) {
+		def.northingFirst = false
+	}
+	return def, nil
+}
+
+// epsgDefinition returns the definition of an EPSG code
+func epsgDefinition(code int) (crsDefinition, error) {
+	def, ok := crsDefs[code]
+	if !ok {
+		return def, fmt.Errorf("%w: EPSG:%d can not be transformed to latitude and longitude", errUnsupportedCRS, code)
+	}
+	return def, nil
+}
+
+// extentAll reads the extents of the local GML and GeoPackage files concurrently,
+// other `data` sources are skipped
+func (r *Resolver) extentAll(uris []string) (map[string]extent, map[string]error) {
+	var local []string
+	for _, uri := range uris {
+		if _, src, err := r.source(uri); err == nil {
+			if _, ok := src.(fileSource); ok && extentReader(uri) != nil {
+				local = append(local, uri)
+			}
+		}
+	}
+	return forAll(r.options.Workers, local, func(uri string) (extent, error) {
+		u, _, err := r.source(uri)
+		if err != nil {
+			return extent{}, err
+		}
+		e, err := extentReader(uri)(filePath(u))
+		if err != nil {
+			return extent{}, fmt.Errorf("could not read the extent: %w", err)
+		}
+		return e, nil
+	})
+}
+
+// extentReader returns the function that reads the extent of a file, based on its extension
+func extentReader(uri string) func(path string) (extent, error) {
+	switch strings.ToLower(filepath.Ext(uri)) {
+	case `.gml`:
+		return gmlExtent
+	case `.gpkg`:
+		return gpkgExtent
+	default:
+		return nil
+	}
+}
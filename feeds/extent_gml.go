@@ -0,0 +1,161 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// gmlElement is the state of an open element while a GML file is streamed
+type gmlElement struct {
+	name      string
+	srsName   string
+	dimension int
+}
+
+// gmlExtent returns the extent of a GML file, this is the boundedBy envelope of the root element
+// or, when that is missing, the extent of all coordinates in the file
+func gmlExtent(path string) (extent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return extent{}, err
+	}
+	defer f.Close()
+
+	// the bounds of the coordinates by srsName, the srsName is inherited from the enclosing elements
+	all := make(map[string]*bounds)
+	var stack []gmlElement
+
+	decoder := xml.NewDecoder(f)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return extent{}, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := gmlElement{name: t.Name.Local, dimension: 2}
+			if len(stack) > 0 {
+				element.srsName, element.dimension = stack[len(stack)-1].srsName, stack[len(stack)-1].dimension
+			}
+			for _, attr := range t.Attr {
+				switch attr.Name.Local {
+				case `srsName`:
+					element.srsName = attr.Value
+				case `srsDimension`:
+					if dimension, err := strconv.Atoi(attr.Value); err == nil && dimension > 1 {
+						element.dimension = dimension
+					}
+				}
+			}
+
+			if !isCoordinateElement(element.name) {
+				stack = append(stack, element)
+				continue
+			}
+			var text string
+			if err := decoder.DecodeElement(&text, &t); err != nil {
+				return extent{}, err
+			}
+			b, ok := all[element.srsName]
+			if !ok {
+				b = newBounds()
+				all[element.srsName] = b
+			}
+			if err := addCoordinates(b, element, text); err != nil {
+				return extent{}, err
+			}
+
+			// the envelope of the root element describes the whole file
+			if len(stack) == 3 && stack[1].name == `boundedBy` && (element.name == `upperCorner` || element.name == `coordinates`) {
+				return toExtent(map[string]*bounds{element.srsName: b})
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return toExtent(all)
+}
+
+// isCoordinateElement returns true for the GML elements that contain coordinates
+func isCoordinateElement(name string) bool {
+	switch name {
+	case `pos`, `posList`, `lowerCorner`, `upperCorner`, `coordinates`:
+		return true
+	}
+	return false
+}
+
+// addCoordinates adds the first two axes of every position in the text to the bounds
+func addCoordinates(b *bounds, element gmlElement, text string) error {
+	var values []string
+	dimension := element.dimension
+	if element.name == `coordinates` {
+		// GML 2 separates the axes with a comma and the positions with whitespace
+		for _, position := range strings.Fields(text) {
+			axes := strings.Split(position, `,`)
+			if len(axes) < 2 {
+				return fmt.Errorf("invalid coordinates %q", position)
+			}
+			values = append(values, axes[0], axes[1])
+		}
+		dimension = 2
+	} else {
+		values = strings.Fields(text)
+	}
+	if element.name == `pos` || element.name == `lowerCorner` || element.name == `upperCorner` {
+		dimension = len(values)
+	}
+	if dimension < 2 || len(values)%dimension != 0 {
+		return fmt.Errorf("invalid %s %q", element.name, text)
+	}
+
+	for i := 0; i < len(values); i += dimension {
+		first, err := strconv.ParseFloat(values[i], 64)
+		if err != nil {
+			return err
+		}
+		second, err := strconv.ParseFloat(values[i+1], 64)
+		if err != nil {
+			return err
+		}
+		b.add(first, second)
+	}
+	return nil
+}
+
+// toExtent transforms and combines the bounds of all srsNames
+func toExtent(all map[string]*bounds) (extent, error) {
+	var e *extent
+	for srsName, b := range all {
+		if b.empty() {
+			continue
+		}
+		if srsName == `` {
+			return extent{}, errors.New(`coordinates without a srsName`)
+		}
+		def, err := srsDefinition(srsName)
+		if err != nil {
+			return extent{}, err
+		}
+		transformed := b.toExtent(def)
+		if e != nil {
+			transformed = e.union(transformed)
+		}
+		e = &transformed
+	}
+	if e == nil {
+		return extent{}, errors.New(`no coordinates found`)
+	}
+	return *e, nil
+}
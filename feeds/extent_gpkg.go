@@ -0,0 +1,114 @@
+package feeds
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	// registers the pure Go sqlite driver, which works without cgo
+	_ "modernc.org/sqlite"
+)
+
+// gpkgExtent returns the extent of a GeoPackage, this is the union of the bounds of its contents in gpkg_contents
+// or, when these are not filled, of the spatial indexes of the feature tables
+func gpkgExtent(path string) (extent, error) {
+	db, err := sql.Open(`sqlite`, (&url.URL{Scheme: `file`, Path: path, RawQuery: `mode=ro`}).String())
+	if err != nil {
+		return extent{}, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT c.table_name, c.min_x, c.min_y, c.max_x, c.max_y, s.organization, s.organization_coordsys_id
+		FROM gpkg_contents c JOIN gpkg_spatial_ref_sys s ON c.srs_id = s.srs_id`)
+	if err != nil {
+		return extent{}, err
+	}
+	defer rows.Close()
+
+	var e *extent
+	for rows.Next() {
+		var table, organization string
+		var minX, minY, maxX, maxY sql.NullFloat64
+		var code int
+		if err := rows.Scan(&table, &minX, &minY, &maxX, &maxY, &organization, &code); err != nil {
+			return extent{}, err
+		}
+
+		b := newBounds()
+		if minX.Valid && minY.Valid && maxX.Valid && maxY.Valid {
+			b.add(minX.Float64, minY.Float64)
+			b.add(maxX.Float64, maxY.Float64)
+		} else if b, err = gpkgIndexBounds(db, table); err != nil {
+			return extent{}, err
+		}
+		if b.empty() {
+			continue
+		}
+
+		if !strings.EqualFold(organization, `EPSG`) {
+			return extent{}, fmt.Errorf("%w: %s has the CRS %s:%d", errUnsupportedCRS, table, organization, code)
+		}
+		def, err := epsgDefinition(code)
+		if err != nil {
+			return extent{}, err
+		}
+		// GeoPackage always has the longitude or easting as x
+		def.northingFirst = false
+
+		transformed := b.toExtent(def)
+		if e != nil {
+			transformed = e.union(transformed)
+		}
+		e = &transformed
+	}
+	if err := rows.Err(); err != nil {
+		return extent{}, err
+	}
+	if e == nil {
+		return extent{}, errors.New(`no bounds found in gpkg_contents`)
+	}
+	return *e, nil
+}
+
+// gpkgIndexBounds returns the bounds of the spatial indexes of a table, which are empty when there is no index
+func gpkgIndexBounds(db *sql.DB, table string) (*bounds, error) {
+	// without the gpkg_extensions table there are no spatial indexes
+	var extensions int
+	if err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'gpkg_extensions'`).Scan(&extensions); err != nil {
+		return nil, err
+	}
+	if extensions == 0 {
+		return newBounds(), nil
+	}
+
+	rows, err := db.Query(`SELECT column_name FROM gpkg_extensions WHERE extension_name = 'gpkg_rtree_index' AND table_name = ?`, table)
+	if err != nil {
+		return nil, err
+	}
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	rows.Close()
+
+	b := newBounds()
+	for _, column := range columns {
+		index := `"rtree_` + strings.ReplaceAll(table+`_`+column, `"`, `""`) + `"`
+		var minX, minY, maxX, maxY sql.NullFloat64
+		if err := db.QueryRow(`SELECT min(minx), min(miny), max(maxx), max(maxy) FROM `+index).Scan(&minX, &minY, &maxX, &maxY); err != nil {
+			return nil, err
+		}
+		if minX.Valid {
+			b.add(minX.Float64, minY.Float64)
+			b.add(maxX.Float64, maxY.Float64)
+		}
+	}
+	return b, nil
+}
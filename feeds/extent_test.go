@@ -0,0 +1,250 @@
+package feeds
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const boundedByGML = `<?xml version="1.0" encoding="UTF-8"?>
+<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:gml="http://www.opengis.net/gml/3.2">
+  <wfs:boundedBy>
+    <gml:Envelope srsName="urn:ogc:def:crs:EPSG::4258">
+      <gml:lowerCorner>50.75 3.2</gml:lowerCorner>
+      <gml:upperCorner>53.7 7.22</gml:upperCorner>
+    </gml:Envelope>
+  </wfs:boundedBy>
+  <wfs:member>
+    <Waterway><geometry><gml:Point srsName="urn:ogc:def:crs:EPSG::4258"><gml:pos>0 0</gml:pos></gml:Point></geometry></Waterway>
+  </wfs:member>
+</wfs:FeatureCollection>`
+
+const coordinatesGML = `<?xml version="1.0" encoding="UTF-8"?>
+<FeatureCollection xmlns:gml="http://www.opengis.net/gml/3.2">
+  <member srsName="http://www.opengis.net/def/crs/EPSG/0/4326">
+    <Waterway><geometry><gml:LineString><gml:posList>50 5 51 5.5</gml:posList></gml:LineString></geometry></Waterway>
+  </member>
+  <member>
+    <Waterway><geometry><gml:Point srsName="http://www.opengis.net/def/crs/OGC/1.3/CRS84"><gml:pos>0 0</gml:pos></gml:Point></geometry></Waterway>
+  </member>
+</FeatureCollection>`
+
+const projectedGML = `<?xml version="1.0" encoding="UTF-8"?>
+<FeatureCollection xmlns:gml="http://www.opengis.net/gml/3.2">
+  <member srsName="http://www.opengis.net/def/crs/EPSG/0/3035">
+    <Waterway><geometry><gml:LineString><gml:posList>2999718.85 3962799.45 2999718.85 3962799.45</gml:posList></gml:LineString></geometry></Waterway>
+  </member>
+</FeatureCollection>`
+
+const utmGML = `<?xml version="1.0" encoding="UTF-8"?>
+<FeatureCollection xmlns:gml="http://www.opengis.net/gml/3.2">
+  <boundedBy>
+    <gml:Envelope srsName="urn:ogc:def:crs:EPSG::25832">
+      <gml:lowerCorner>500000 0</gml:lowerCorner>
+      <gml:upperCorner>500000 0</gml:upperCorner>
+    </gml:Envelope>
+  </boundedBy>
+</FeatureCollection>`
+
+const unsupportedGML = `<?xml version="1.0" encoding="UTF-8"?>
+<FeatureCollection xmlns:gml="http://www.opengis.net/gml/3.2">
+  <member srsName="http://www.opengis.net/def/crs/EPSG/0/3006">
+    <Waterway><geometry><gml:Point><gml:pos>6580000 674000</gml:pos></gml:Point></geometry></Waterway>
+  </member>
+</FeatureCollection>`
+
+func writeGeoPackage(t *testing.T, path string) {
+	t.Helper()
+	db, err := sql.Open(`sqlite`, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, statement := range []string{
+		`CREATE TABLE gpkg_spatial_ref_sys (srs_name TEXT, srs_id INTEGER PRIMARY KEY, organization TEXT, organization_coordsys_id INTEGER, definition TEXT)`,
+		`CREATE TABLE gpkg_contents (table_name TEXT PRIMARY KEY, data_type TEXT, min_x DOUBLE, min_y DOUBLE, max_x DOUBLE, max_y DOUBLE, srs_id INTEGER)`,
+		`INSERT INTO gpkg_spatial_ref_sys VALUES ('ETRS89', 4258, 'EPSG', 4258, '')`,
+		`INSERT INTO gpkg_contents VALUES ('waterway', 'features', 4.5, 51.0, 8.0, 54.0, 4258)`,
+		`INSERT INTO gpkg_contents VALUES ('lock', 'features', NULL, NULL, NULL, NULL, 4258)`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestProcessFeedsExtent(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"waternetwork_4258.gml":  boundedByGML,
+		"waternetwork_4326.gml":  coordinatesGML,
+		"waternetwork_3035.gml":  projectedGML,
+		"waternetwork_25832.gml": utmGML,
+		"waternetwork_3006.gml":  unsupportedGML,
+		"invalid.gml":            "<FeatureCollection><pos>1 2</pos></FeatureCollection>",
+		"readme.txt":             "water network",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeGeoPackage(t, filepath.Join(dir, "waternetwork_4258.gpkg"))
+
+	link := func(name string) Link {
+		return Link{Href: "http://xyz.org/data/abc/" + name, Data: sp(filepath.Join(dir, name))}
+	}
+	withBbox := link("waternetwork_4326.gml")
+	withBbox.Bbox = sp("1 1 2 2")
+
+	var tests = []struct {
+		links    []Link
+		polygon  string
+		bboxes   []*string
+		valid    bool
+		warnings []Finding
+	}{
+		// the root envelope of a GML file
+		0: {links: []Link{link("waternetwork_4258.gml")}, polygon: "50.75 3.2 53.7 3.2 53.7 7.22 50.75 7.22 50.75 3.2",
+			bboxes: []*string{sp("50.75 3.2 53.7 7.22")}, valid: true},
+		// the coordinates of a GML file in multiple CRSs
+		1: {links: []Link{link("waternetwork_4326.gml")}, polygon: "0 0 51 0 51 5.5 0 5.5 0 0",
+			bboxes: []*string{sp("0 0 51 5.5")}, valid: true},
+		// the polygon covers all links, an existing bbox is kept and other files have no extent
+		2: {links: []Link{link("waternetwork_4258.gml"), link("waternetwork_4258.gpkg"), link("readme.txt")},
+			polygon: "50.75 3.2 54 3.2 54 8 50.75 8 50.75 3.2",
+			bboxes:  []*string{sp("50.75 3.2 53.7 7.22"), sp("51 4.5 54 8"), nil}, valid: true},
+		3: {links: []Link{withBbox}, polygon: "0 0 51 0 51 5.5 0 5.5 0 0", bboxes: []*string{sp("1 1 2 2")}, valid: true},
+		// coordinates without a srsName can not be transformed
+		4: {links: []Link{link("invalid.gml")}, valid: false},
+		// data in a projected CRS is transformed to latitude and longitude
+		5: {links: []Link{link("waternetwork_3035.gml")}, polygon: "50 5 50 5 50 5 50 5 50 5",
+			bboxes: []*string{sp("50 5 50 5")}, valid: true},
+		6: {links: []Link{link("waternetwork_25832.gml")}, polygon: "0 9 0 9 0 9 0 9 0 9",
+			bboxes: []*string{sp("0 9 0 9")}, valid: true},
+		// data in an unsupported CRS has no extent, which is a warning
+		7: {links: []Link{link("waternetwork_3006.gml")}, bboxes: []*string{nil}, valid: true, warnings: []Finding{
+			{Severity: SeverityWarning, Requirement: `TG Recommendation 10`, Path: `entry[0].link[0].bbox`,
+				Message: "missing 'link.bbox', the extent of the data could not be read: " +
+					"could not read the extent: unsupported CRS: EPSG:3006 can not be transformed to latitude and longitude see TG Recommendation 10"},
+		}},
+	}
+
+	for k, test := range tests {
+		input := Feeds{Feeds: []Feed{{
+			ID:    "http://xyz.org/data/abc/waternetwork.xml",
+			Entry: []Entry{{ID: "http://xyz.org/data/abc/waternetwork", Link: test.links}},
+		}}}
+		output, err := ProcessFeeds(input, Options{ExtentFromData: true, Resolver: NewResolver(testOptions())})
+		if (err == nil) != test.valid {
+			t.Errorf("test: %d, expected valid: %t \ngot: %v", k, test.valid, err)
+		}
+		if !test.valid {
+			continue
+		}
+
+		entry := output[0].Entry[0]
+		if entry.Polygon != test.polygon {
+			t.Errorf("test: %d, expected polygon: %s \ngot: %s", k, test.polygon, entry.Polygon)
+		}
+		var bboxes []*string
+		for _, link := range entry.Link {
+			bboxes = append(bboxes, link.Bbox)
+		}
+		if !reflect.DeepEqual(bboxes, test.bboxes) {
+			t.Errorf("test: %d, expected bboxes: %v \ngot: %v", k, test.bboxes, bboxes)
+		}
+		var warnings []Finding
+		report := output[0].Valid()
+		for _, finding := range report.Warnings() {
+			if finding.Requirement == `TG Recommendation 10` {
+				warnings = append(warnings, finding)
+			}
+		}
+		if !reflect.DeepEqual(warnings, test.warnings) {
+			t.Errorf("test: %d, expected warnings: %v \ngot: %v", k, test.warnings, warnings)
+		}
+	}
+}
+
+func TestSRSDefinition(t *testing.T) {
+	var tests = []struct {
+		srsName       string
+		northingFirst bool
+		err           error
+	}{
+		0: {srsName: "urn:ogc:def:crs:EPSG::4258", northingFirst: true},
+		1: {srsName: "http://www.opengis.net/def/crs/EPSG/0/4326", northingFirst: true},
+		2: {srsName: "EPSG:4258", northingFirst: false},
+		3: {srsName: "http://www.opengis.net/gml/srs/epsg.xml#4258", northingFirst: false},
+		4: {srsName: "http://www.opengis.net/def/crs/OGC/1.3/CRS84", northingFirst: false},
+		5: {srsName: "http://www.opengis.net/def/crs/EPSG/0/28992", northingFirst: false},
+		6: {srsName: "urn:ogc:def:crs:EPSG::3044", northingFirst: true},
+		7: {srsName: "http://www.opengis.net/def/crs/EPSG/0/3006", err: errUnsupportedCRS},
+		8: {srsName: "urn:x-unknown:crs", err: errUnsupportedCRS},
+	}
+
+	for k, test := range tests {
+		def, err := srsDefinition(test.srsName)
+		if !errors.Is(err, test.err) {
+			t.Errorf("test: %d, expected error: %v \ngot: %v", k, test.err, err)
+		}
+		if err == nil && def.northingFirst != test.northingFirst {
+			t.Errorf("test: %d, expected northing first: %t", k, test.northingFirst)
+		}
+	}
+}
+
+func TestGpkgExtentIndex(t *testing.T) {
+	var tests = []struct {
+		statements []string
+		box        string
+		valid      bool
+		err        string
+	}{
+		// the bounds of the spatial index when gpkg_contents has none
+		0: {statements: []string{
+			`CREATE TABLE gpkg_extensions (table_name TEXT, column_name TEXT, extension_name TEXT)`,
+			`INSERT INTO gpkg_extensions VALUES ('waterway', 'geom', 'gpkg_rtree_index')`,
+			`CREATE TABLE rtree_waterway_geom (id INTEGER, minx DOUBLE, maxx DOUBLE, miny DOUBLE, maxy DOUBLE)`,
+			`INSERT INTO rtree_waterway_geom VALUES (1, 4.5, 5, 51, 52), (2, 6, 8, 53, 54)`,
+		}, box: "51 4.5 54 8", valid: true},
+		// without the gpkg_extensions table there are no spatial indexes
+		1: {valid: false, err: "no bounds found in gpkg_contents"},
+		// other errors are returned, not taken for a missing index
+		2: {statements: []string{`CREATE TABLE gpkg_extensions (table_name TEXT)`}, valid: false, err: "no such column"},
+	}
+
+	for k, test := range tests {
+		path := filepath.Join(t.TempDir(), "waternetwork.gpkg")
+		db, err := sql.Open(`sqlite`, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, statement := range append([]string{
+			`CREATE TABLE gpkg_spatial_ref_sys (srs_name TEXT, srs_id INTEGER PRIMARY KEY, organization TEXT, organization_coordsys_id INTEGER, definition TEXT)`,
+			`CREATE TABLE gpkg_contents (table_name TEXT PRIMARY KEY, data_type TEXT, min_x DOUBLE, min_y DOUBLE, max_x DOUBLE, max_y DOUBLE, srs_id INTEGER)`,
+			`INSERT INTO gpkg_spatial_ref_sys VALUES ('ETRS89', 4258, 'EPSG', 4258, '')`,
+			`INSERT INTO gpkg_contents VALUES ('waterway', 'features', NULL, NULL, NULL, NULL, 4258)`,
+		}, test.statements...) {
+			if _, err := db.Exec(statement); err != nil {
+				t.Fatal(err)
+			}
+		}
+		db.Close()
+
+		e, err := gpkgExtent(path)
+		if (err == nil) != test.valid {
+			t.Errorf("test: %d, expected valid: %t \ngot: %v", k, test.valid, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), test.err) {
+			t.Errorf("test: %d, expected error: %s \ngot: %v", k, test.err, err)
+		}
+		if err == nil && e.box() != test.box {
+			t.Errorf("test: %d, expected box: %s \ngot: %s", k, test.box, e.box())
+		}
+	}
+}
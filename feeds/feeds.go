@@ -2,49 +2,98 @@ package feeds
 
 import (
 	"encoding/xml"
-	"errors"
 	"fmt"
-	"log"
 	"net/url"
 	"os"
 	"regexp"
-	"sort"
+	"slices"
 	"strings"
 	"time"
 )
 
 // Feeds struct
 type Feeds struct {
-	Feeds []Feed `yaml:"feeds"`
+	S3 S3 `yaml:"s3,omitempty"`
+	// Defaults are the values of the feeds that are not set, see Feed.withDefaults
+	Defaults *Feed  `yaml:"defaults,omitempty"`
+	Feeds    []Feed `yaml:"feeds"`
 }
 
 // Feed struct
 //
 //nolint:tagliatelle
 type Feed struct {
-	XMLName       xml.Name `xml:"feed"`
-	XMLStylesheet *string  `yaml:"stylesheet"`
-	Xmlns         string   `xml:"xmlns,attr" yaml:"xmlns"`                                       // "http://www.w3.org/2005/Atom"
-	Georss        string   `xml:"xmlns:georss,attr,omitempty" yaml:"georss,omitempty"`           // "http://www.georss.org/georss"
-	InspireDls    string   `xml:"xmlns:inspire_dls,attr,omitempty" yaml:"inspire_dls,omitempty"` // "http://inspire.ec.europa.eu/schemas/inspire_dls/1.0"
-	Lang          *string  `xml:"xml:lang,attr,omitempty" yaml:"lang,omitempty"`
-
-	ID       string `xml:"id" yaml:"id"`
-	Title    string `xml:"title" yaml:"title"`
-	Subtitle string `xml:"subtitle" yaml:"subtitle"`
+	XMLName       xml.Name `xml:"feed" yaml:"-" json:"-"`
+	XMLStylesheet *string  `yaml:"stylesheet,omitempty" json:"-"`
+	Xmlns         string   `xml:"xmlns,attr" yaml:"xmlns" json:"-"`                                       // "http://www.w3.org/2005/Atom"
+	Georss        string   `xml:"xmlns:georss,attr,omitempty" yaml:"georss,omitempty" json:"-"`           // "http://www.georss.org/georss"
+	InspireDls    string   `xml:"xmlns:inspire_dls,attr,omitempty" yaml:"inspire_dls,omitempty" json:"-"` // "http://inspire.ec.europa.eu/schemas/inspire_dls/1.0"
+	Gml           string   `xml:"xmlns:gml,attr,omitempty" yaml:"gml,omitempty" json:"-"`                 // "http://www.opengis.net/gml", when an entry has a 'where'
+	Lang          *string  `xml:"xml:lang,attr,omitempty" yaml:"lang,omitempty" json:"lang,omitempty"`
+	// Attrs are the namespace declarations of extension attributes, like the checksum attribute
+	Attrs []xml.Attr `xml:",any,attr" yaml:"-" json:"-"`
+
+	ID       string `xml:"id" yaml:"id" json:"id,omitempty"`
+	Title    string `xml:"title" yaml:"title" json:"title,omitempty"`
+	Subtitle string `xml:"subtitle" yaml:"subtitle" json:"subtitle,omitempty"`
 
 	// Placeholder Links, need to be moved to []Link and deleted
-	Self        *Link `xml:"self,omitempty" yaml:"self,omitempty"`
-	Describedby *Link `xml:"describedby,omitempty" yaml:"describedby,omitempty"`
-	Search      *Link `xml:"search,omitempty" yaml:"search,omitempty"`
-	Up          *Link `xml:"up,omitempty" yaml:"up,omitempty"`
+	Self        *Link `xml:"self,omitempty" yaml:"self,omitempty" json:"-"`
+	Describedby *Link `xml:"describedby,omitempty" yaml:"describedby,omitempty" json:"-"`
+	Search      *Link `xml:"search,omitempty" yaml:"search,omitempty" json:"-"`
+	Up          *Link `xml:"up,omitempty" yaml:"up,omitempty" json:"-"`
+
+	Link []Link `xml:"link" yaml:"link" json:"link,omitempty"`
 
-	Link []Link `xml:"link" yaml:"link"`
+	Rights  string  `xml:"rights" yaml:"rights" json:"rights,omitempty"`
+	Updated *string `xml:"updated" yaml:"updated,omitempty" json:"updated,omitempty"`
+	Author  Author  `xml:"author" yaml:"author" json:"author,omitzero"`
+	Entry   []Entry `xml:"entry" yaml:"entry" json:"entry,omitempty"`
 
-	Rights  string  `xml:"rights" yaml:"rights"`
-	Updated *string `xml:"updated" yaml:"updated,omitempty"`
-	Author  Author  `xml:"author" yaml:"author"`
-	Entry   []Entry `xml:"entry" yaml:"entry"`
+	// Generate expands into entries for the files that are found by the generators, see EntryGenerator
+	Generate []EntryGenerator `xml:"-" yaml:"generate,omitempty" json:"-"`
+}
+
+// FeedKind distinguishes a service feed from a dataset feed
+type FeedKind string
+
+const (
+	// ServiceFeed is the top level feed of a download service, with one entry per dataset
+	ServiceFeed FeedKind = `service`
+	// DatasetFeed is the feed of a single dataset, with one entry per download
+	DatasetFeed FeedKind = `dataset`
+)
+
+// Kind classifies the Feed, a service feed is recognised by its search link
+// or by entries that link to other ATOM feeds, a dataset feed by its up link
+func (f *Feed) Kind() FeedKind {
+	if len(f.Links(search)) > 0 {
+		return ServiceFeed
+	}
+	if len(f.Links(up)) > 0 {
+		return DatasetFeed
+	}
+	for _, entry := range f.Entry {
+		if slices.ContainsFunc(entry.Link, isFeedLink) {
+			return ServiceFeed
+		}
+	}
+	return DatasetFeed
+}
+
+// Links returns all links of the Feed with the given rel, including the predefined links
+func (f *Feed) Links(rel string) []Link {
+	var links []Link
+	for _, l := range f.Link {
+		if l.Rel == rel {
+			links = append(links, l)
+		}
+	}
+	predefined := map[string]*Link{self: f.Self, describedby: f.Describedby, search: f.Search, up: f.Up}
+	if l, ok := predefined[rel]; ok && l != nil {
+		links = append(links, *l)
+	}
+	return links
 }
 
 // GetFileName function
@@ -63,23 +112,29 @@ func (f *Feed) GetFileName() (string, error) {
 }
 
 // GenerateATOM function build a ATOM feed from the configuration
-func (f *Feed) GenerateATOM() []byte {
+func (f *Feed) GenerateATOM() ([]byte, error) {
 	stylesheet := f.StyleSheet()
 	f.XMLStylesheet = nil
 
-	si, _ := xml.MarshalIndent(f, "", " ")
-	return append(append([]byte(xml.Header), stylesheet...), si...)
+	si, err := xml.MarshalIndent(f, "", " ")
+	if err != nil {
+		return nil, &ProcessError{FeedID: f.ID, Err: err}
+	}
+	return append(append([]byte(xml.Header), stylesheet...), si...), nil
 }
 
 // WriteATOM function writes the ATOM feed to file
 //
 //nolint:gosec
-func (f *Feed) WriteATOM(filename string) {
-	b := f.GenerateATOM()
-	err := os.WriteFile(filename, b, 0777)
+func (f *Feed) WriteATOM(filename string) error {
+	b, err := f.GenerateATOM()
 	if err != nil {
-		log.Fatalf("Could not write to file %s : %v ", filename, err)
+		return err
 	}
+	if err := os.WriteFile(filename, b, 0777); err != nil {
+		return &ProcessError{FeedID: f.ID, Err: fmt.Errorf("could not write to file %s: %w", filename, err)}
+	}
+	return nil
 }
 
 // StyleSheet function returns a xml-stylesheet header if available
@@ -92,64 +147,82 @@ func (f *Feed) StyleSheet() []byte {
 	return []byte(``)
 }
 
+// Valid validates all the Feeds and the references between them based on TG Requirements
+// the paths of the findings are prefixed with the index of the feed, e.g. feeds[1].title
+func (fs Feeds) Valid() Report {
+	var report Report
+	for index, feed := range fs.Feeds {
+		report.Merge(fmt.Sprintf("feeds[%d]", index), feed.Valid())
+	}
+	report.Merge(``, fs.Consistent())
+	return report
+}
+
 // Valid function that validates the Feed based on TG Requirements
-// For now a simple validation
+// it returns a Report containing all violated TG Requirements and Recommendations
 //
 //nolint:cyclop
-func (f *Feed) Valid() error {
+func (f *Feed) Valid() Report {
+	var report Report
 
 	// TG Requirement 5
 	// The 'title' element of an Atom feed shall be populated with a human readable title for the feed.
 	if len(f.Title) == 0 {
-		return errors.New(invalidtitle)
+		report.Error(`TG Requirement 5`, `title`, invalidtitle)
 	}
 
 	// TG Recommendation 1
 	// The 'subtitle' element of an Atom feed may be populated with a human readable subtitle for the feed.
 	if len(f.Subtitle) == 0 {
-		log.Println(warningsubtitle)
+		report.Warning(`TG Recommendation 1`, `subtitle`, warningsubtitle)
+	}
+
+	// TG Requirement 7
+	// A feed shall contain a 'link' element with rel="self" that points to the feed itself.
+	if len(f.Links(self)) == 0 {
+		report.Error(`TG Requirement 7`, `link`, invalidself)
 	}
 
 	// TG Requirement 9
 	// The 'id' element of a feed shall contain an HTTP URI which dereferences to the feed
 	_, err := url.ParseRequestURI(f.ID)
 	if err != nil {
-		return errors.New(invalidid)
+		report.Error(`TG Requirement 9`, `id`, invalidid)
 	}
 
 	// TG Requirement 10
 	// The 'rights' element of a feed shall contain information about rights or restrictions for that feed.
 	if len(f.Rights) == 0 {
-		return errors.New(invalidrights)
+		report.Error(`TG Requirement 10`, `rights`, invalidrights)
 	}
 
 	// TG Requirement 11
 	// The 'updated' element of a feed shall contain the date, time and timezone at which the feed was last updated.
-	for _, entry := range f.Entry {
+	for entryIndex, entry := range f.Entry {
+		path := fmt.Sprintf("entry[%d].updated", entryIndex)
 		if entry.Updated == nil {
-			return errors.New(invalidupdated)
-		}
-		if _, err := time.Parse(`2006-01-02T15:04:05Z`, *entry.Updated); err != nil {
-			return errors.New(invaliddatetime)
+			report.Error(`TG Requirement 11`, path, invalidupdated)
+		} else if _, err := parseTime(*entry.Updated); err != nil {
+			report.Error(`TG Requirement 11`, path, invaliddatetime)
 		}
 	}
 	if f.Updated == nil {
-		return errors.New(invalidupdated)
-	}
-	if _, err := time.Parse(`2006-01-02T15:04:05Z`, *f.Updated); err != nil {
-		return errors.New(invaliddatetime)
+		report.Error(`TG Requirement 11`, `updated`, invalidupdated)
+	} else if _, err := parseTime(*f.Updated); err != nil {
+		report.Error(`TG Requirement 11`, `updated`, invaliddatetime)
 	}
 
 	// TG Recommendation 11
 	// Where a dataset is provided in multiple physical files: a `time` attribute may be used to describe the temporal extent of a particular file.
-	// If this is used, then the value of this attribute should be structured according to the ISO 8601 standard.
-	for _, entry := range f.Entry {
-		for _, link := range entry.Link {
+	// If this is used, then the value of this attribute should be structured according to the ISO 8601 standard,
+	// either a single timestamp or an interval (start/end).
+	for entryIndex, entry := range f.Entry {
+		for linkIndex, link := range entry.Link {
 			if link.Time == nil {
 				continue
 			}
-			if _, err := time.Parse(`2006-01-02T15:04:05Z`, *link.Time); err != nil {
-				return errors.New(invalidlinktime)
+			if _, _, err := parseInterval(*link.Time); err != nil {
+				report.Warning(`TG Recommendation 11`, fmt.Sprintf("entry[%d].link[%d].time", entryIndex, linkIndex), warninglinktime)
 			}
 		}
 	}
@@ -158,14 +231,34 @@ func (f *Feed) Valid() error {
 	// Where a dataset is provided in multiple physical files: a `bbox` attribute may be used to describe the geospatial extent of a particular file.
 	// If this is used, then the value of this attribute should be structured according to the georss:box structure.
 	re := regexp.MustCompile(`^-?\d+(\.\d+)? -?\d+(\.\d+)? -?\d+(\.\d+)? -?\d+(\.\d+)?$`)
-	for _, entry := range f.Entry {
-		for _, link := range entry.Link {
+	for entryIndex, entry := range f.Entry {
+		for linkIndex, link := range entry.Link {
+			if link.noExtent != nil {
+				report.Warning(`TG Recommendation 10`, fmt.Sprintf("entry[%d].link[%d].bbox", entryIndex, linkIndex), fmt.Sprintf(warningnoextent, link.noExtent))
+			}
 			if link.Bbox == nil {
 				continue
 			}
 			matched := re.MatchString(*link.Bbox)
 			if !matched {
-				return errors.New(invalidlinkbbox)
+				report.Warning(`TG Recommendation 10`, fmt.Sprintf("entry[%d].link[%d].bbox", entryIndex, linkIndex), warninglinkbbox)
+			}
+		}
+	}
+
+	// TG Requirement 17
+	// The georss geometries of an entry need latitude longitude pairs, a 'polygon' needs to be a closed ring
+	// and the gml:Envelope of a 'where' needs a srsName.
+	for entryIndex, entry := range f.Entry {
+		entry.validGeoRSS(&report, fmt.Sprintf("entry[%d]", entryIndex))
+	}
+
+	// TG Requirement 22
+	// The EPSG codes of the `crs` shorthand need to be known to derive their CRS categories.
+	for entryIndex, entry := range f.Entry {
+		for crsIndex, code := range entry.CRS {
+			if _, ok := EPSGCategory(code); !ok {
+				report.Error(`TG Requirement 22`, fmt.Sprintf("entry[%d].crs[%d]", entryIndex, crsIndex), invalidentryepsg)
 			}
 		}
 	}
@@ -173,10 +266,94 @@ func (f *Feed) Valid() error {
 	// TG Requirement 12
 	// The 'author' element of a feed shall contain current contact information for an individual or organisation responsible for the feed. At the minimum, a name and email address shall be provided as contact information.
 	if len(f.Author.Name) == 0 || len(f.Author.Email) == 0 {
-		return errors.New(invalidauthor)
+		report.Error(`TG Requirement 12`, `author`, invalidauthor)
 	}
 
-	return nil
+	switch f.Kind() {
+	case ServiceFeed:
+		f.validServiceFeed(&report)
+	case DatasetFeed:
+		f.validDatasetFeed(&report)
+	}
+
+	return report
+}
+
+// validServiceFeed validates the TG Requirements that only apply to a service feed
+func (f *Feed) validServiceFeed(report *Report) {
+	// TG Requirement 6
+	// A service feed shall contain a 'link' element with rel="describedby" that points to the metadata of the download service.
+	if len(f.Links(describedby)) == 0 {
+		report.Error(`TG Requirement 6`, `link`, invaliddescribedby)
+	}
+
+	// TG Requirement 8
+	// A service feed shall contain a 'link' element with rel="search" that points to the OpenSearch description document.
+	if len(f.Links(search)) == 0 {
+		report.Error(`TG Requirement 8`, `link`, invalidsearch)
+	}
+
+	for entryIndex, entry := range f.Entry {
+		path := fmt.Sprintf("entry[%d]", entryIndex)
+
+		// TG Requirement 15
+		// A service feed entry shall contain the code and namespace of the spatial dataset identifier of the dataset.
+		if entry.SpatialDatasetIdentifierCode == nil || len(*entry.SpatialDatasetIdentifierCode) == 0 {
+			report.Error(`TG Requirement 15`, path+`.spatial_dataset_identifier_code`, invalididentifiercode)
+		}
+		if entry.SpatialDatasetIdentifierNamespace == nil || len(*entry.SpatialDatasetIdentifierNamespace) == 0 {
+			report.Error(`TG Requirement 15`, path+`.spatial_dataset_identifier_namespace`, invalididentifierns)
+		}
+
+		// TG Requirement 16
+		// A service feed entry shall contain a 'link' element with rel="alternate" that points to the dataset feed.
+		if !slices.ContainsFunc(entry.Link, isFeedLink) {
+			report.Error(`TG Requirement 16`, path+`.link`, invalidentryfeedlink)
+		}
+	}
+}
+
+// validDatasetFeed validates the TG Requirements that only apply to a dataset feed
+func (f *Feed) validDatasetFeed(report *Report) {
+	for entryIndex, entry := range f.Entry {
+		path := fmt.Sprintf("entry[%d]", entryIndex)
+
+		// TG Requirement 22
+		// A dataset feed entry shall describe the CRS of the download with a 'category' element.
+		if !slices.ContainsFunc(entry.Category, isCRSCategory) {
+			report.Error(`TG Requirement 22`, path+`.category`, invalidentrycrs)
+		}
+
+		for linkIndex, link := range entry.Link {
+			// links with data are not resolved yet or skipped, their type is filled by ProcessFeeds
+			if !isDownloadLink(link) || link.Data != nil || link.skipped {
+				continue
+			}
+			linkPath := fmt.Sprintf("%s.link[%d]", path, linkIndex)
+
+			// TG Requirement 20
+			// The 'type' attribute of a download link shall contain the media type of the download.
+			if len(link.Type) == 0 {
+				report.Error(`TG Requirement 20`, linkPath+`.type`, invalidlinktype)
+			}
+
+			// TG Requirement 21
+			// The 'hreflang' attribute of a download link shall contain the language of the download.
+			if link.Hreflang == nil || len(*link.Hreflang) == 0 {
+				report.Error(`TG Requirement 21`, linkPath+`.hreflang`, invalidlinkhreflang)
+			}
+		}
+	}
+}
+
+// isDownloadLink returns true when the link points to (a part of) a download
+func isDownloadLink(l Link) bool {
+	return l.Rel == alternate || l.Rel == section
+}
+
+// isCRSCategory returns true when the category term is a CRS URI
+func isCRSCategory(c Category) bool {
+	return strings.Contains(c.Term, `/def/crs/`)
 }
 
 // Function that retrieves values from updated fields and returns the most recent updated field
@@ -206,55 +383,85 @@ func (e Entry) nestedFeed(feeds []Feed) *Feed {
 }
 
 func (f *Feed) recentUpdatedEntry() *string {
-	var updatedFields []string
+	var recent *string
+	var recentTime time.Time
 
 	for _, entry := range f.Entry {
-		if entry.Updated != nil {
-			updatedFields = append(updatedFields, *entry.Updated)
+		if entry.Updated == nil {
+			continue
+		}
+		// compare the parsed times, comparing the strings gives the wrong answer across timezones
+		t, err := parseTime(*entry.Updated)
+		if err != nil {
+			continue
+		}
+		if recent == nil || t.After(recentTime) {
+			recent = entry.Updated
+			recentTime = t
 		}
 	}
-	sort.Sort(sort.Reverse(sort.StringSlice(updatedFields)))
-	if len(updatedFields) == 0 {
-		return nil
+	return recent
+}
+
+// normalizeTimes rewrites the 'updated' and 'link.time' values to their canonical form
+func (f *Feed) normalizeTimes() {
+	f.Updated = normalizeTime(f.Updated)
+	for entryIndex, entry := range f.Entry {
+		f.Entry[entryIndex].Updated = normalizeTime(entry.Updated)
+		for linkIndex, link := range entry.Link {
+			entry.Link[linkIndex].Time = normalizeInterval(link.Time)
+		}
 	}
-	return &updatedFields[0]
 }
 
 // Entry struct
 //
 //nolint:tagliatelle
 type Entry struct {
-	ID                                string     `xml:"id" yaml:"id"`
-	Title                             string     `xml:"title,omitempty" yaml:"title,omitempty"`
-	Content                           string     `xml:"content,omitempty" yaml:"content,omitempty"`
-	Summary                           string     `xml:"summary,omitempty" yaml:"summary,omitempty"`
-	Link                              []Link     `xml:"link" yaml:"link"`
-	Rights                            string     `xml:"rights,omitempty" yaml:"rights,omitempty"`
-	Updated                           *string    `xml:"updated" yaml:"updated,omitempty"`
-	Polygon                           string     `xml:"georss:polygon,omitempty" yaml:"polygon,omitempty"`
-	Category                          []Category `xml:"category" yaml:"category"`
-	SpatialDatasetIdentifierCode      *string    `xml:"inspire_dls:spatial_dataset_identifier_code,omitempty" yaml:"spatial_dataset_identifier_code,omitempty"`
-	SpatialDatasetIdentifierNamespace *string    `xml:"inspire_dls:spatial_dataset_identifier_namespace,omitempty" yaml:"spatial_dataset_identifier_namespace,omitempty"`
+	ID                                string     `xml:"id" yaml:"id" json:"id,omitempty"`
+	Title                             string     `xml:"title,omitempty" yaml:"title,omitempty" json:"title,omitempty"`
+	Content                           string     `xml:"content,omitempty" yaml:"content,omitempty" json:"content,omitempty"`
+	Summary                           string     `xml:"summary,omitempty" yaml:"summary,omitempty" json:"summary,omitempty"`
+	Link                              []Link     `xml:"link" yaml:"link" json:"link,omitempty"`
+	Rights                            string     `xml:"rights,omitempty" yaml:"rights,omitempty" json:"rights,omitempty"`
+	Updated                           *string    `xml:"updated" yaml:"updated,omitempty" json:"updated,omitempty"`
+	Polygon                           string     `xml:"georss:polygon,omitempty" yaml:"polygon,omitempty" json:"polygon,omitempty"`
+	Box                               string     `xml:"georss:box,omitempty" yaml:"box,omitempty" json:"box,omitempty"`
+	Point                             string     `xml:"georss:point,omitempty" yaml:"point,omitempty" json:"point,omitempty"`
+	Line                              string     `xml:"georss:line,omitempty" yaml:"line,omitempty" json:"line,omitempty"`
+	Where                             *Where     `xml:"georss:where,omitempty" yaml:"where,omitempty" json:"where,omitempty"`
+	Category                          []Category `xml:"category" yaml:"category" json:"category,omitempty"`
+	CRS                               []int      `xml:"-" yaml:"crs,omitempty" json:"-"`
+	SpatialDatasetIdentifierCode      *string    `xml:"inspire_dls:spatial_dataset_identifier_code,omitempty" yaml:"spatial_dataset_identifier_code,omitempty" json:"spatial_dataset_identifier_code,omitempty"`
+	SpatialDatasetIdentifierNamespace *string    `xml:"inspire_dls:spatial_dataset_identifier_namespace,omitempty" yaml:"spatial_dataset_identifier_namespace,omitempty" json:"spatial_dataset_identifier_namespace,omitempty"`
 }
 
 // Author struct
 type Author struct {
-	Name  string `xml:"name" yaml:"name"`
-	Email string `xml:"email" yaml:"email"`
+	Name  string `xml:"name" yaml:"name" json:"name,omitempty"`
+	Email string `xml:"email" yaml:"email" json:"email,omitempty"`
 }
 
 // Link struct
 type Link struct {
-	Href     string  `xml:"href,attr" yaml:"href"`
-	Data     *string `xml:"data,attr,omitempty" yaml:"data,omitempty"`
-	Rel      string  `xml:"rel,attr,omitempty" yaml:"rel,omitempty"`
-	Type     string  `xml:"type,attr,omitempty" yaml:"type,omitempty"`
-	Hreflang *string `xml:"hreflang,attr,omitempty" yaml:"hreflang,omitempty"`
-	Length   string  `xml:"length,attr,omitempty" yaml:"length,omitempty"`
-	Title    string  `xml:"title,attr,omitempty" yaml:"title,omitempty"`
-	Version  *string `xml:"version,attr,omitempty" yaml:"version,omitempty"`
-	Time     *string `xml:"time,attr,omitempty" yaml:"time,omitempty"`
-	Bbox     *string `xml:"bbox,attr,omitempty" yaml:"bbox,omitempty"`
+	Href     string  `xml:"href,attr" yaml:"href" json:"href,omitempty"`
+	Data     *string `xml:"data,attr,omitempty" yaml:"data,omitempty" json:"-"`
+	Rel      string  `xml:"rel,attr,omitempty" yaml:"rel,omitempty" json:"rel,omitempty"`
+	Type     string  `xml:"type,attr,omitempty" yaml:"type,omitempty" json:"type,omitempty"`
+	Hreflang *string `xml:"hreflang,attr,omitempty" yaml:"hreflang,omitempty" json:"hreflang,omitempty"`
+	Length   string  `xml:"length,attr,omitempty" yaml:"length,omitempty" json:"length,omitempty"`
+	Title    string  `xml:"title,attr,omitempty" yaml:"title,omitempty" json:"title,omitempty"`
+	Version  *string `xml:"version,attr,omitempty" yaml:"version,omitempty" json:"version,omitempty"`
+	Time     *string `xml:"time,attr,omitempty" yaml:"time,omitempty" json:"time,omitempty"`
+	Bbox     *string `xml:"bbox,attr,omitempty" yaml:"bbox,omitempty" json:"bbox,omitempty"`
+	// SHA256 is the checksum of the download, it is computed from the `data` when not provided
+	SHA256 *string `xml:"-" yaml:"sha256,omitempty" json:"sha256,omitempty"`
+	// Attrs are the extension attributes of the link, like the checksum attribute
+	Attrs []xml.Attr `xml:",any,attr" yaml:"-" json:"-"`
+	// skipped marks a link of which the `data` is not resolved, because the data access is skipped
+	skipped bool
+	// noExtent is the reason that the extent of the `data` could not be read
+	noExtent error
 }
 
 // SetHrefLang function assigns a default Lang is none is given
@@ -268,6 +475,6 @@ func (l *Link) SetHrefLang(lang string) Link {
 
 // Category struct
 type Category struct {
-	Term  string `xml:"term,attr" yaml:"term"`
-	Label string `xml:"label,attr" yaml:"label"`
+	Term  string `xml:"term,attr" yaml:"term" json:"term,omitempty"`
+	Label string `xml:"label,attr" yaml:"label" json:"label,omitempty"`
 }
@@ -1,8 +1,8 @@
 package feeds
 
 import (
-	"errors"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -330,8 +330,15 @@ func TestGenerateATOM(t *testing.T) {
 	}
 
 	for k, test := range tests {
-		p := ProcessFeeds(test.input)
-		output := p[0].GenerateATOM()
+		p, err := ProcessFeeds(test.input, Options{})
+		if err != nil {
+			t.Errorf("test: %d, unexpected error: %v", k, err)
+			continue
+		}
+		output, err := p[0].GenerateATOM()
+		if err != nil {
+			t.Errorf("test: %d, unexpected error: %v", k, err)
+		}
 		if string(output) != test.expected {
 			t.Errorf("test: %d, expected: %s \ngot: %s", k, test.expected, string(output))
 		}
@@ -365,11 +372,12 @@ func TestValid(t *testing.T) {
 	var updated = "2021-03-31T13:45:03Z"
 	var tests = []struct {
 		input    Feed
-		expected error
+		expected []Finding
 	}{
 		0: {
 			input: Feed{
 				ID:      "http://xyz.org/download/en.xml",
+				Self:    &Link{Href: "http://xyz.org/download/en.xml"},
 				Title:   "XYZ Example INSPIRE Download Service",
 				Rights:  "Copyright (c) 2012, XYZ; all rights reserved",
 				Updated: &updated,
@@ -383,15 +391,17 @@ func TestValid(t *testing.T) {
 		1: {
 			input: Feed{
 				ID:      "http://xyz.org/download/en.xml",
+				Self:    &Link{Href: "http://xyz.org/download/en.xml"},
 				Title:   "XYZ Example INSPIRE Download Service",
 				Rights:  "Copyright (c) 2012, XYZ; all rights reserved",
 				Updated: &updated,
 			},
-			expected: errors.New(invalidauthor),
+			expected: []Finding{{Severity: SeverityError, Requirement: `TG Requirement 12`, Path: `author`, Message: invalidauthor}},
 		},
 		2: {
 			input: Feed{
 				ID:      "http://xyz.org/download/en.xml",
+				Self:    &Link{Href: "http://xyz.org/download/en.xml"},
 				Title:   "XYZ Example INSPIRE Download Service",
 				Updated: &updated,
 				Author: Author{
@@ -399,11 +409,12 @@ func TestValid(t *testing.T) {
 					Email: "doe@xyz.org",
 				},
 			},
-			expected: errors.New(invalidrights),
+			expected: []Finding{{Severity: SeverityError, Requirement: `TG Requirement 10`, Path: `rights`, Message: invalidrights}},
 		},
 		3: {
 			input: Feed{
 				ID:     "http://xyz.org/download/en.xml",
+				Self:   &Link{Href: "http://xyz.org/download/en.xml"},
 				Rights: "Copyright (c) 2012, XYZ; all rights reserved",
 				Title:  "XYZ Example INSPIRE Download Service",
 				Author: Author{
@@ -411,73 +422,93 @@ func TestValid(t *testing.T) {
 					Email: "doe@xyz.org",
 				},
 			},
-			expected: errors.New(invalidupdated),
+			expected: []Finding{{Severity: SeverityError, Requirement: `TG Requirement 11`, Path: `updated`, Message: invalidupdated}},
 		},
 		4: {
 			input: Feed{
 				ID:    "xyzorgdownloaden.xml",
+				Self:  &Link{Href: "xyzorgdownloaden.xml"},
 				Title: "XYZ Example INSPIRE Download Service",
 			},
-			expected: errors.New(invalidid),
+			expected: []Finding{
+				{Severity: SeverityError, Requirement: `TG Requirement 9`, Path: `id`, Message: invalidid},
+				{Severity: SeverityError, Requirement: `TG Requirement 10`, Path: `rights`, Message: invalidrights},
+				{Severity: SeverityError, Requirement: `TG Requirement 11`, Path: `updated`, Message: invalidupdated},
+				{Severity: SeverityError, Requirement: `TG Requirement 12`, Path: `author`, Message: invalidauthor},
+			},
 		},
 		5: {
 			input: Feed{
-				ID: "http://xyz.org/download/en.xml",
+				ID:   "http://xyz.org/download/en.xml",
+				Self: &Link{Href: "http://xyz.org/download/en.xml"},
+			},
+			expected: []Finding{
+				{Severity: SeverityError, Requirement: `TG Requirement 5`, Path: `title`, Message: invalidtitle},
+				{Severity: SeverityError, Requirement: `TG Requirement 10`, Path: `rights`, Message: invalidrights},
+				{Severity: SeverityError, Requirement: `TG Requirement 11`, Path: `updated`, Message: invalidupdated},
+				{Severity: SeverityError, Requirement: `TG Requirement 12`, Path: `author`, Message: invalidauthor},
 			},
-			expected: errors.New(invalidtitle),
 		},
 		6: {
 			input: Feed{
 				ID:      "http://boo.bar/baz.xml",
+				Self:    &Link{Href: "http://boo.bar/baz.xml"},
 				Title:   "foo",
 				Rights:  "foo",
 				Updated: &updated,
 				Author:  Author{Name: "foo", Email: "bar@baz.cuz"},
 				Entry: []Entry{{
-					Updated: nil,
+					Updated:  nil,
+					Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/4258", Label: "ETRS89"}},
 				}},
 			},
-			expected: errors.New(invalidupdated),
+			expected: []Finding{{Severity: SeverityError, Requirement: `TG Requirement 11`, Path: `entry[0].updated`, Message: invalidupdated}},
 		},
 		7: {
 			input: Feed{
 				ID:      "http://boo.bar/baz.xml",
+				Self:    &Link{Href: "http://boo.bar/baz.xml"},
 				Title:   "foo",
 				Rights:  "foo",
 				Updated: &updated,
 				Author:  Author{Name: "foo", Email: "bar@baz.cuz"},
 				Entry: []Entry{{
-					Updated: &updated,
+					Updated:  &updated,
+					Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/4258", Label: "ETRS89"}},
 					Link: []Link{{
 						Bbox: sp("1 2.0 3.14 a"),
 					}}}},
 			},
-			expected: errors.New(invalidlinkbbox),
+			expected: nil,
 		},
 		8: {
 			input: Feed{
 				ID:      "http://boo.bar/baz.xml",
+				Self:    &Link{Href: "http://boo.bar/baz.xml"},
 				Title:   "foo",
 				Rights:  "foo",
 				Updated: &updated,
 				Author:  Author{Name: "foo", Email: "bar@baz.cuz"},
 				Entry: []Entry{{
-					Updated: &updated,
+					Updated:  &updated,
+					Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/4258", Label: "ETRS89"}},
 					Link: []Link{{
 						Time: sp("0000-00-00T00:00:00Z"),
 					}}}},
 			},
-			expected: errors.New(invalidlinktime),
+			expected: nil,
 		},
 		9: {
 			input: Feed{
 				ID:      "http://boo.bar/baz.xml",
+				Self:    &Link{Href: "http://boo.bar/baz.xml"},
 				Title:   "foo",
 				Rights:  "foo",
 				Updated: &updated,
 				Author:  Author{Name: "foo", Email: "bar@baz.cuz"},
 				Entry: []Entry{{
-					Updated: &updated,
+					Updated:  &updated,
+					Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/4258", Label: "ETRS89"}},
 					Link: []Link{{
 						Time: sp("1970-01-01T00:00:00Z"),
 						Bbox: sp("1 2 3 4"),
@@ -485,23 +516,174 @@ func TestValid(t *testing.T) {
 			},
 			expected: nil,
 		},
+		10: {
+			input: Feed{
+				ID:      "http://xyz.org/download/en.xml",
+				Self:    &Link{Href: "http://xyz.org/download/en.xml"},
+				Search:  &Link{Href: "http://xyz.org/search/opensearchdescription.xml"},
+				Title:   "XYZ Example INSPIRE Download Service",
+				Rights:  "Copyright (c) 2012, XYZ; all rights reserved",
+				Updated: &updated,
+				Author:  Author{Name: "John Doe", Email: "doe@xyz.org"},
+				Entry: []Entry{{
+					Updated: &updated,
+					Link:    []Link{{Rel: "describedby", Href: "http://xyz.org/metadata/abcISO19139.xml"}},
+				}},
+			},
+			expected: []Finding{
+				{Severity: SeverityError, Requirement: `TG Requirement 6`, Path: `link`, Message: invaliddescribedby},
+				{Severity: SeverityError, Requirement: `TG Requirement 15`, Path: `entry[0].spatial_dataset_identifier_code`, Message: invalididentifiercode},
+				{Severity: SeverityError, Requirement: `TG Requirement 15`, Path: `entry[0].spatial_dataset_identifier_namespace`, Message: invalididentifierns},
+				{Severity: SeverityError, Requirement: `TG Requirement 16`, Path: `entry[0].link`, Message: invalidentryfeedlink},
+			},
+		},
+		11: {
+			input: Feed{
+				ID:      "http://xyz.org/data/abc/waternetwork.xml",
+				Self:    &Link{Href: "http://xyz.org/data/abc/waternetwork.xml"},
+				Up:      &Link{Href: "http://xyz.org/download/en.xml"},
+				Title:   "XYZ Example INSPIRE Dataset ABC Download",
+				Rights:  "Copyright (c) 2012, XYZ; all rights reserved",
+				Updated: &updated,
+				Author:  Author{Name: "John Doe", Email: "doe@xyz.org"},
+				Entry: []Entry{{
+					Updated:  &updated,
+					Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/25832", Label: "ETRS89 / UTM zone 32N"}},
+					Link: []Link{
+						{Rel: "alternate", Href: "http://xyz.org/data/abc/waternetwork_25832.gml"},
+						{Rel: "alternate", Href: "http://xyz.org/data/abc/waternetwork_25832.zip", Data: sp("http://backend/waternetwork_25832.zip")},
+						{Rel: "section", Href: "http://xyz.org/data/abc/waternetwork_25832_2.zip", Type: "application/zip", Hreflang: sp("en")},
+					},
+				}, {
+					Updated:  &updated,
+					Category: []Category{{Term: "Hydrography", Label: "Hydrography"}},
+				}},
+			},
+			expected: []Finding{
+				{Severity: SeverityError, Requirement: `TG Requirement 20`, Path: `entry[0].link[0].type`, Message: invalidlinktype},
+				{Severity: SeverityError, Requirement: `TG Requirement 21`, Path: `entry[0].link[0].hreflang`, Message: invalidlinkhreflang},
+				{Severity: SeverityError, Requirement: `TG Requirement 22`, Path: `entry[1].category`, Message: invalidentrycrs},
+			},
+		},
+		12: {
+			input: Feed{
+				ID:      "http://boo.bar/baz.xml",
+				Self:    &Link{Href: "http://boo.bar/baz.xml"},
+				Title:   "foo",
+				Rights:  "foo",
+				Updated: sp("2024-03-01T10:00:00.5+01:00"),
+				Author:  Author{Name: "foo", Email: "bar@baz.cuz"},
+				Entry: []Entry{{
+					Updated:  sp("2024-03-01T10:00:00+01:00"),
+					Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/4258", Label: "ETRS89"}},
+					Link: []Link{{
+						Time: sp("2024-01-01T00:00:00+01:00/2024-12-31T23:59:59+01:00"),
+					}}}},
+			},
+			expected: nil,
+		},
+		13: {
+			input: Feed{
+				ID:      "http://boo.bar/baz.xml",
+				Self:    &Link{Href: "http://boo.bar/baz.xml"},
+				Title:   "foo",
+				Rights:  "foo",
+				Updated: &updated,
+				Author:  Author{Name: "foo", Email: "bar@baz.cuz"},
+				Entry: []Entry{{
+					Updated:  &updated,
+					Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/25832", Label: "ETRS89 / UTM zone 32N"}},
+					CRS:      []int{25832, 99999},
+				}},
+			},
+			expected: []Finding{{Severity: SeverityError, Requirement: `TG Requirement 22`, Path: `entry[0].crs[1]`, Message: invalidentryepsg}},
+		},
 	}
 
 	for k, test := range tests {
-		b := test.input.Valid()
-
-		if b == nil && !errors.Is(b, test.expected) {
-			t.Errorf("test: %d, expected: %t \ngot: %t", k, test.expected, b)
+		report := test.input.Valid()
+		if !reflect.DeepEqual(report.Errors(), test.expected) {
+			t.Errorf("test: %d, expected: %v \ngot: %v", k, test.expected, report.Errors())
 		}
+	}
+}
 
-		if b != nil {
-			if test.expected != nil {
-				if b.Error() != test.expected.Error() {
-					t.Errorf("test: %d, expected: %t \ngot: %t", k, test.expected, b)
-				}
-			} else {
-				t.Errorf("test: %d, expected: %t \ngot: %t", k, test.expected, b)
-			}
+func TestValidWarnings(t *testing.T) {
+	var updated = "2021-03-31T13:45:03Z"
+	var tests = []struct {
+		input    Feeds
+		expected Report
+	}{
+		0: {
+			input: Feeds{Feeds: []Feed{{
+				ID:       "http://xyz.org/download/en.xml",
+				Self:     &Link{Href: "http://xyz.org/download/en.xml"},
+				Title:    "XYZ Example INSPIRE Download Service",
+				Subtitle: "INSPIRE Download Service of organisation XYZ",
+				Rights:   "Copyright (c) 2012, XYZ; all rights reserved",
+				Updated:  &updated,
+				Author:   Author{Name: "John Doe", Email: "doe@xyz.org"},
+			}}},
+			expected: Report{},
+		},
+		1: {
+			input: Feeds{Feeds: []Feed{
+				{
+					ID:       "http://xyz.org/download/en.xml",
+					Self:     &Link{Href: "http://xyz.org/download/en.xml"},
+					Title:    "XYZ Example INSPIRE Download Service",
+					Subtitle: "INSPIRE Download Service of organisation XYZ",
+					Rights:   "Copyright (c) 2012, XYZ; all rights reserved",
+					Updated:  &updated,
+					Author:   Author{Name: "John Doe", Email: "doe@xyz.org"},
+				},
+				{
+					ID:      "http://xyz.org/data/abc/en.xml",
+					Self:    &Link{Href: "http://xyz.org/data/abc/en.xml"},
+					Title:   "XYZ Example INSPIRE Dataset ABC Download",
+					Rights:  "Copyright (c) 2012, XYZ; all rights reserved",
+					Updated: &updated,
+					Author:  Author{Name: "John Doe", Email: "doe@xyz.org"},
+					Entry: []Entry{{
+						Updated:  &updated,
+						Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/4258", Label: "ETRS89"}},
+						Link:     []Link{{Bbox: sp("1 2 3")}},
+					}},
+				},
+			}},
+			expected: Report{Findings: []Finding{
+				{Severity: SeverityWarning, Requirement: `TG Recommendation 1`, Path: `feeds[1].subtitle`, Message: warningsubtitle},
+				{Severity: SeverityWarning, Requirement: `TG Recommendation 10`, Path: `feeds[1].entry[0].link[0].bbox`, Message: warninglinkbbox},
+			}},
+		},
+		2: {
+			input: Feeds{Feeds: []Feed{{
+				ID:       "http://xyz.org/data/abc/en.xml",
+				Self:     &Link{Href: "http://xyz.org/data/abc/en.xml"},
+				Title:    "XYZ Example INSPIRE Dataset ABC Download",
+				Subtitle: "INSPIRE Download Service of organisation XYZ",
+				Rights:   "Copyright (c) 2012, XYZ; all rights reserved",
+				Updated:  &updated,
+				Author:   Author{Name: "John Doe", Email: "doe@xyz.org"},
+				Entry: []Entry{{
+					Updated:  &updated,
+					Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/4258", Label: "ETRS89"}},
+					Link:     []Link{{Time: sp("0000-00-00T00:00:00Z")}},
+				}},
+			}}},
+			expected: Report{Findings: []Finding{
+				{Severity: SeverityWarning, Requirement: `TG Recommendation 11`, Path: `feeds[0].entry[0].link[0].time`, Message: warninglinktime},
+			}},
+		},
+	}
+
+	for k, test := range tests {
+		report := test.input.Valid()
+		if !reflect.DeepEqual(report, test.expected) {
+			t.Errorf("test: %d, expected: %v \ngot: %v", k, test.expected, report)
+		}
+		if report.HasErrors() != test.expected.HasErrors() {
+			t.Errorf("test: %d, expected errors: %t \ngot: %t", k, test.expected.HasErrors(), report.HasErrors())
 		}
 	}
 }
@@ -528,7 +710,9 @@ func TestFeedWriteATOM(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			f := &Feed{}
 
-			f.WriteATOM(tt.args.filename)
+			if err := f.WriteATOM(tt.args.filename); err != nil {
+				t.Errorf("Error occurred: %s", err)
+			}
 			_, err := os.Stat(tt.args.filename)
 			err2 := os.Remove(tt.args.filename)
 			if err2 != nil {
@@ -0,0 +1,238 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// placeholder matches a {name} in the templates of an EntryGenerator
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// EntryGenerator generates an Entry for every file under Source that matches Glob or Regex,
+// the ID, Title, Href and Category are templates with placeholders like {name},
+// see EntryGenerator.placeholders for the available placeholders
+//
+//nolint:tagliatelle
+type EntryGenerator struct {
+	// Source is a local directory or a `s3://bucket/prefix`, which is searched recursively
+	Source string `yaml:"source"`
+	// Glob matches the path relative to the Source, e.g. `waternetwork_*.gml`
+	Glob string `yaml:"glob,omitempty"`
+	// Regex matches the whole path relative to the Source, named groups like (?P<crs>\d+) are placeholders
+	Regex    string     `yaml:"regex,omitempty"`
+	ID       string     `yaml:"id"`
+	Title    string     `yaml:"title,omitempty"`
+	Href     string     `yaml:"href"`
+	Rel      string     `yaml:"rel,omitempty"`
+	Type     string     `yaml:"type,omitempty"`
+	Category []Category `yaml:"category,omitempty"`
+}
+
+// Listed is a file that is found by Resolver.List
+type Listed struct {
+	// URI is the `data` of the file
+	URI string
+	// Path is the slash separated path of the file relative to the listed prefix
+	Path string
+	// Resource contains the properties known from the listing, the type is not known
+	Resource Resource
+}
+
+// lister is a source that can list the files under a prefix
+type lister interface {
+	list(ctx context.Context, prefix *url.URL) ([]Listed, error)
+}
+
+// List lists the files under a local directory or a `s3://bucket/prefix`, failed requests are retried with a backoff
+func (r *Resolver) List(ctx context.Context, prefix string) ([]Listed, error) {
+	u, src, err := r.source(prefix)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := src.(lister)
+	if !ok {
+		return nil, fmt.Errorf("%s can not be listed, only local directories and s3 prefixes can", prefix)
+	}
+	if _, local := src.(fileSource); r.options.Offline && !local {
+		return nil, fmt.Errorf("%s can not be listed offline", prefix)
+	}
+	listed, err := retry(ctx, r.options, func() ([]Listed, error) {
+		return l.list(ctx, u)
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(listed, func(a, b Listed) int { return strings.Compare(a.Path, b.Path) })
+	return listed, nil
+}
+
+// generate returns the entries for the matching files, with skipData only a local Source is listed
+func (g EntryGenerator) generate(ctx context.Context, resolver *Resolver, skipData bool) ([]Entry, error) {
+	match, err := g.matcher()
+	if err != nil {
+		return nil, err
+	}
+	if skipData {
+		if _, src, err := resolver.source(g.Source); err == nil {
+			if _, local := src.(fileSource); !local {
+				return nil, fmt.Errorf("generator %s needs data access to list its files, which is skipped", g.Source)
+			}
+		}
+	}
+	listed, err := resolver.List(ctx, g.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range listed {
+		values, ok := match(file.Path)
+		if !ok {
+			continue
+		}
+		entry, err := g.entry(file, values)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Path, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// matcher returns a function that matches a relative path and returns the values of the named groups
+func (g EntryGenerator) matcher() (func(string) (map[string]string, bool), error) {
+	switch {
+	case g.Glob != `` && g.Regex != ``:
+		return nil, fmt.Errorf("generator %s has both a glob and a regex", g.Source)
+	case g.Regex != ``:
+		re, err := regexp.Compile(`^(?:` + g.Regex + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("generator %s has an invalid regex: %w", g.Source, err)
+		}
+		return func(p string) (map[string]string, bool) {
+			match := re.FindStringSubmatch(p)
+			if match == nil {
+				return nil, false
+			}
+			values := make(map[string]string)
+			for i, name := range re.SubexpNames() {
+				if name != `` {
+					values[name] = match[i]
+				}
+			}
+			return values, true
+		}, nil
+	default:
+		glob := g.Glob
+		if glob == `` {
+			glob = `*`
+		}
+		if _, err := path.Match(glob, ``); err != nil {
+			return nil, fmt.Errorf("generator %s has an invalid glob: %w", g.Source, err)
+		}
+		return func(p string) (map[string]string, bool) {
+			matched, _ := path.Match(glob, p)
+			return map[string]string{}, matched
+		}, nil
+	}
+}
+
+// entry fills the templates with the placeholders of the file
+func (g EntryGenerator) entry(file Listed, values map[string]string) (Entry, error) {
+	for name, value := range g.placeholders(file) {
+		if _, ok := values[name]; !ok {
+			values[name] = value
+		}
+	}
+
+	var errs []string
+	expand := func(template string) string {
+		return placeholder.ReplaceAllStringFunc(template, func(p string) string {
+			value, ok := values[p[1:len(p)-1]]
+			if !ok {
+				errs = append(errs, p)
+				return p
+			}
+			return value
+		})
+	}
+
+	rel := g.Rel
+	if rel == `` {
+		rel = alternate
+	}
+	data := file.URI
+	entry := Entry{
+		ID:    expand(g.ID),
+		Title: expand(g.Title),
+		Link:  []Link{{Href: expand(g.Href), Rel: rel, Type: g.Type, Data: &data}},
+	}
+	for _, category := range g.Category {
+		entry.Category = append(entry.Category, Category{Term: expand(category.Term), Label: expand(category.Label)})
+	}
+
+	if len(errs) > 0 {
+		return entry, fmt.Errorf("unknown placeholder(s) %s", strings.Join(errs, `, `))
+	}
+	return entry, nil
+}
+
+// placeholders returns the placeholders that are available for every file:
+// {path} the relative path, {name} the file name, {stem} the file name without extension and {ext} the extension
+func (g EntryGenerator) placeholders(file Listed) map[string]string {
+	name := path.Base(file.Path)
+	ext := path.Ext(name)
+	return map[string]string{
+		`path`: file.Path,
+		`name`: name,
+		`stem`: strings.TrimSuffix(name, ext),
+		`ext`:  strings.TrimPrefix(ext, `.`),
+	}
+}
+
+// Generated returns a copy of the Feeds in which the generators of the feeds are expanded into entries,
+// with SkipData only the generators of local directories are expanded, because the others need network access,
+// the returned error joins a *ProcessError for every feed for which the generation failed
+func (fs Feeds) Generated(ctx context.Context, options Options) (Feeds, error) {
+	generated, errs := fs.generated(ctx, options.resolver(fs), options.SkipData)
+	return generated, errors.Join(errs...)
+}
+
+// generated returns a copy of the Feeds in which the generators of the feeds are expanded into entries,
+// the generated entries follow the configured entries, feeds for which the generation failed are left out
+func (fs Feeds) generated(ctx context.Context, resolver *Resolver, skipData bool) (Feeds, []error) {
+	feeds := make([]Feed, 0, len(fs.Feeds))
+	var errs []error
+
+	for _, f := range fs.Feeds {
+		if len(f.Generate) == 0 {
+			feeds = append(feeds, f)
+			continue
+		}
+
+		entries := slices.Clone(f.Entry)
+		var err error
+		for _, g := range f.Generate {
+			var generated []Entry
+			if generated, err = g.generate(ctx, resolver, skipData); err != nil {
+				break
+			}
+			entries = append(entries, generated...)
+		}
+		if err != nil {
+			errs = append(errs, &ProcessError{FeedID: f.ID, Err: err})
+			continue
+		}
+		f.Entry = entries
+		f.Generate = nil
+		feeds = append(feeds, f)
+	}
+	fs.Feeds = feeds
+	return fs, errs
+}
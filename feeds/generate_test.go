@@ -0,0 +1,150 @@
+package feeds
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestProcessFeedsGenerate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"waternetwork_25832.gml", "waternetwork_4258.gml", "readme.txt", "sub/waternetwork_3035.gml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("<FeatureCollection/>"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := S3{Region: "eu-central-1", AccessKey: "minio", SecretKey: "minio123"}
+	server := httptest.NewServer(fakeS3(t, config, map[string]string{
+		"/abc/data/waternetwork_25832.zip": "PK\x03\x04",
+		"/abc/data/waternetwork_4258.zip":  "PK\x03\x04",
+		"/abc/other/waternetwork_3035.zip": "PK\x03\x04",
+	}))
+	defer server.Close()
+	config.Endpoint = server.URL
+	options := testOptions()
+	options.S3 = config
+
+	gml := EntryGenerator{
+		Source:   dir,
+		Regex:    `(.*/)?waternetwork_(?P<crs>\d+)\.gml`,
+		ID:       "http://xyz.org/data/abc/waternetwork_{crs}.gml",
+		Title:    "Water network in CRS EPSG:{crs} (GML)",
+		Href:     "http://xyz.org/data/abc/{path}",
+		Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/{crs}", Label: "EPSG:{crs}"}},
+	}
+	zip := EntryGenerator{
+		Source: "s3://abc/data",
+		Glob:   "*.zip",
+		ID:     "http://xyz.org/data/abc/{name}",
+		Href:   "http://xyz.org/data/abc/{stem}.{ext}",
+	}
+
+	var tests = []struct {
+		generators []EntryGenerator
+		expected   [][]string
+		valid      bool
+	}{
+		// the entries are sorted by their path
+		0: {generators: []EntryGenerator{gml}, expected: [][]string{
+			{"http://xyz.org/data/abc/waternetwork_3035.gml", "Water network in CRS EPSG:3035 (GML)", "http://xyz.org/data/abc/sub/waternetwork_3035.gml", "application/gml+xml", "http://www.opengis.net/def/crs/EPSG/0/3035"},
+			{"http://xyz.org/data/abc/waternetwork_25832.gml", "Water network in CRS EPSG:25832 (GML)", "http://xyz.org/data/abc/waternetwork_25832.gml", "application/gml+xml", "http://www.opengis.net/def/crs/EPSG/0/25832"},
+			{"http://xyz.org/data/abc/waternetwork_4258.gml", "Water network in CRS EPSG:4258 (GML)", "http://xyz.org/data/abc/waternetwork_4258.gml", "application/gml+xml", "http://www.opengis.net/def/crs/EPSG/0/4258"},
+		}, valid: true},
+		1: {generators: []EntryGenerator{zip}, expected: [][]string{
+			{"http://xyz.org/data/abc/waternetwork_25832.zip", "", "http://xyz.org/data/abc/waternetwork_25832.zip", "application/gml+xml", ""},
+			{"http://xyz.org/data/abc/waternetwork_4258.zip", "", "http://xyz.org/data/abc/waternetwork_4258.zip", "application/gml+xml", ""},
+		}, valid: true},
+		2: {generators: []EntryGenerator{{Source: dir, Glob: "*.gml", ID: "http://xyz.org/data/abc/{crs}", Href: "http://xyz.org/data/abc/{name}"}}, valid: false},
+		3: {generators: []EntryGenerator{{Source: "http://xyz.org/data/abc", ID: "{name}", Href: "{name}"}}, valid: false},
+	}
+
+	for k, test := range tests {
+		input := Feeds{Feeds: []Feed{{ID: "http://xyz.org/data/abc/waternetwork.xml", Generate: test.generators}}}
+		output, err := ProcessFeeds(input, Options{Resolver: NewResolver(options), UpdatedFromData: true})
+		if (err == nil) != test.valid {
+			t.Errorf("test: %d, expected valid: %t \ngot: %v", k, test.valid, err)
+		}
+		if !test.valid {
+			continue
+		}
+
+		var got [][]string
+		for _, entry := range output[0].Entry {
+			var category string
+			if len(entry.Category) > 0 {
+				category = entry.Category[0].Term
+			}
+			got = append(got, []string{entry.ID, entry.Title, entry.Link[0].Href, entry.Link[0].Type, category})
+			if entry.Updated == nil || entry.Link[0].Length == "" || entry.Link[0].Rel != "alternate" {
+				t.Errorf("test: %d, expected the updated, length and rel to be set: %+v", k, entry)
+			}
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test: %d, expected: %v \ngot: %v", k, test.expected, got)
+		}
+		if output[0].Generate != nil {
+			t.Errorf("test: %d, expected the generators to be removed", k)
+		}
+
+		// the updated of generated entries comes from their data only when asked for
+		output, err = ProcessFeeds(input, Options{Resolver: NewResolver(options)})
+		if err != nil {
+			t.Fatalf("test: %d, unexpected error: %v", k, err)
+		}
+		for _, entry := range output[0].Entry {
+			if entry.Updated != nil {
+				t.Errorf("test: %d, expected no updated without UpdatedFromData: %+v", k, entry)
+			}
+		}
+	}
+}
+
+func TestGeneratedSkipData(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "waternetwork_25832.gml"), []byte("<FeatureCollection/>"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	options := testOptions()
+	options.S3 = S3{Endpoint: server.URL, Region: "eu-central-1", AccessKey: "minio", SecretKey: "minio123"}
+	processOptions := Options{SkipData: true, Resolver: NewResolver(options)}
+
+	local := Feeds{Feeds: []Feed{{ID: "http://xyz.org/data/abc/waternetwork.xml", Generate: []EntryGenerator{
+		{Source: dir, Glob: "*.gml", ID: "http://xyz.org/data/abc/{name}", Href: "http://xyz.org/data/abc/{name}"},
+	}}}}
+	generated, err := local.Generated(context.Background(), processOptions)
+	if err != nil || len(generated.Feeds[0].Entry) != 1 {
+		t.Errorf("expected a local directory to be listed with SkipData, got %+v, %v", generated.Feeds, err)
+	}
+
+	for _, source := range []string{"s3://abc/data", server.URL + "/data"} {
+		remote := Feeds{Feeds: []Feed{{ID: "http://xyz.org/data/abc/waternetwork.xml", Generate: []EntryGenerator{
+			{Source: source, ID: "http://xyz.org/data/abc/{name}", Href: "http://xyz.org/data/abc/{name}"},
+		}}}}
+		_, err := remote.Generated(context.Background(), processOptions)
+		if err == nil || !strings.Contains(err.Error(), "needs data access to list its files, which is skipped") {
+			t.Errorf("expected a data access error for %s, got %v", source, err)
+		}
+		if _, err := ProcessFeeds(remote, processOptions); err == nil {
+			t.Errorf("expected a data access error for %s", source)
+		}
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("expected no requests with SkipData, got %d", n)
+	}
+}
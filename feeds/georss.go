@@ -0,0 +1,144 @@
+package feeds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const gmlNamespace = `http://www.opengis.net/gml`
+
+// Where is a georss:where, which describes the extent of an entry with a GML envelope in any CRS
+type Where struct {
+	Envelope Envelope `xml:"gml:Envelope" yaml:"envelope" json:"envelope,omitzero"`
+}
+
+// Envelope is a gml:Envelope, the corners are in the axis order of the srsName
+//
+//nolint:tagliatelle
+type Envelope struct {
+	SrsName     string `xml:"srsName,attr" yaml:"srs_name" json:"srs_name,omitempty"`
+	LowerCorner string `xml:"gml:lowerCorner" yaml:"lower_corner" json:"lower_corner,omitempty"`
+	UpperCorner string `xml:"gml:upperCorner" yaml:"upper_corner" json:"upper_corner,omitempty"`
+}
+
+// europe is the area in which the INSPIRE data is expected, a geometry that only falls within it
+// when its pairs are read as longitude latitude is most likely swapped
+var europe = extent{minLat: 27, minLon: -32, maxLat: 85, maxLon: 45}
+
+// position is a latitude longitude pair of a georss geometry
+type position struct {
+	lat, lon float64
+}
+
+// geometry is one of the simple georss geometries of an entry, with the number of positions it needs
+type geometry struct {
+	name     string
+	value    string
+	min, max int
+	closed   bool
+}
+
+// geometries returns the simple georss geometries of the entry
+func (e Entry) geometries() []geometry {
+	return []geometry{
+		{name: `polygon`, value: e.Polygon, min: 4, closed: true},
+		{name: `line`, value: e.Line, min: 2},
+		{name: `point`, value: e.Point, min: 1, max: 1},
+		{name: `box`, value: e.Box, min: 2, max: 2},
+	}
+}
+
+// validGeoRSS validates the georss geometries of an entry, the messages contain the ID of the entry
+func (e Entry) validGeoRSS(report *Report, path string) {
+	for _, g := range e.geometries() {
+		if g.value != `` {
+			e.validGeometry(report, joinPath(path, g.name), g)
+		}
+	}
+	if e.Where != nil {
+		e.validWhere(report, joinPath(path, `where`))
+	}
+}
+
+// validGeometry validates a simple georss geometry: latitude longitude pairs within range
+func (e Entry) validGeometry(report *Report, path string, g geometry) {
+	values, ok := parseNumbers(g.value)
+	if !ok {
+		report.Error(`TG Requirement 17`, path, fmt.Sprintf(invalidgeorss, g.name, e.ID))
+		return
+	}
+	if len(values)%2 != 0 {
+		report.Error(`TG Requirement 17`, path, fmt.Sprintf(invalidgeorssodd, g.name, e.ID))
+	}
+	positions := make([]position, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		positions = append(positions, position{lat: values[i], lon: values[i+1]})
+	}
+
+	switch {
+	case g.max == g.min && len(positions) != g.min:
+		report.Error(`TG Requirement 17`, path, fmt.Sprintf(invalidgeorsspositions, g.name, e.ID, strconv.Itoa(g.min)))
+	case len(positions) < g.min:
+		report.Error(`TG Requirement 17`, path, fmt.Sprintf(invalidgeorsspositions, g.name, e.ID, `at least `+strconv.Itoa(g.min)))
+	}
+	if g.closed && len(positions) > 0 && positions[0] != positions[len(positions)-1] {
+		report.Error(`TG Requirement 17`, path, fmt.Sprintf(invalidpolygonclosed, e.ID))
+	}
+	if g.name == `box` && len(positions) == 2 && (positions[0].lat > positions[1].lat || positions[0].lon > positions[1].lon) {
+		report.Error(`TG Requirement 17`, path, fmt.Sprintf(invalidgeorsscorners, g.name, e.ID))
+	}
+
+	inRange, inEurope, swappedInEurope := true, true, true
+	for _, p := range positions {
+		inRange = inRange && p.lat >= -90 && p.lat <= 90 && p.lon >= -180 && p.lon <= 180
+		inEurope = inEurope && europe.contains(p.lat, p.lon)
+		swappedInEurope = swappedInEurope && europe.contains(p.lon, p.lat)
+	}
+	if !inRange {
+		report.Error(`TG Requirement 17`, path, fmt.Sprintf(invalidgeorssrange, g.name, e.ID))
+	}
+	if len(positions) > 0 && !inEurope && swappedInEurope {
+		report.Warning(`TG Requirement 17`, path, fmt.Sprintf(warninggeorssswapped, g.name, e.ID))
+	}
+}
+
+// validWhere validates the gml:Envelope of a georss:where, its corners are in the CRS of the srsName
+func (e Entry) validWhere(report *Report, path string) {
+	envelope := e.Where.Envelope
+	if strings.TrimSpace(envelope.SrsName) == `` {
+		report.Error(`TG Requirement 17`, path+`.envelope.srs_name`, fmt.Sprintf(invalidwheresrsname, e.ID))
+	}
+
+	lower, lowerOK := parseNumbers(envelope.LowerCorner)
+	upper, upperOK := parseNumbers(envelope.UpperCorner)
+	if !lowerOK || !upperOK || len(lower) < 2 || len(lower) != len(upper) {
+		report.Error(`TG Requirement 17`, path+`.envelope`, fmt.Sprintf(invalidwherecorners, e.ID))
+		return
+	}
+	for i := range lower {
+		if lower[i] > upper[i] {
+			report.Error(`TG Requirement 17`, path+`.envelope`, fmt.Sprintf(invalidgeorsscorners, `where`, e.ID))
+			return
+		}
+	}
+}
+
+// parseNumbers parses a whitespace separated list of numbers
+func parseNumbers(value string) ([]float64, bool) {
+	fields := strings.Fields(value)
+	numbers := make([]float64, 0, len(fields))
+	for _, field := range fields {
+		number, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, false
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, true
+}
+
+// contains returns true when the position is within the extent
+func (e extent) contains(lat, lon float64) bool {
+	return lat >= e.minLat && lat <= e.maxLat && lon >= e.minLon && lon <= e.maxLon
+}
@@ -0,0 +1,111 @@
+package feeds
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEntryValidGeoRSS(t *testing.T) {
+	id := "http://xyz.org/data/abc/waternetwork_25832.gml"
+	finding := func(severity Severity, path, message string, args ...any) Finding {
+		return Finding{Severity: severity, Requirement: `TG Requirement 17`, Path: `entry[0].` + path, Message: fmt.Sprintf(message, args...)}
+	}
+	envelope := func(srsName, lower, upper string) *Where {
+		return &Where{Envelope: Envelope{SrsName: srsName, LowerCorner: lower, UpperCorner: upper}}
+	}
+
+	var tests = []struct {
+		input    Entry
+		expected []Finding
+	}{
+		0: {input: Entry{}, expected: nil},
+		1: {input: Entry{
+			Polygon: "47.202 5.755 55.183 5.755 55.183 15.253 47.202 15.253 47.202 5.755",
+			Line:    "52.1 5.1 52.2 5.2",
+			Point:   "52.155172 5.387204",
+			Box:     "47.202 5.755 55.183 15.253",
+			Where:   envelope("http://www.opengis.net/def/crs/EPSG/0/28992", "13565.4 306846.2", "278026.1 619315.3"),
+		}, expected: nil},
+		2: {input: Entry{Polygon: "47.202 5.755 55.183 5.755 55.183 15.253 47.202 15.253 47.202"}, expected: []Finding{
+			finding(SeverityError, `polygon`, invalidgeorssodd, `polygon`, id),
+			finding(SeverityError, `polygon`, invalidpolygonclosed, id),
+		}},
+		3: {input: Entry{Polygon: "47.202 5.755 55.183 5.755 47.202 5.755"}, expected: []Finding{
+			finding(SeverityError, `polygon`, invalidgeorsspositions, `polygon`, id, `at least 4`),
+		}},
+		4: {input: Entry{Polygon: "47.202 5.755 55.183 5.755 55.183 15.253 47.202 15.253 47.202 5.756"}, expected: []Finding{
+			finding(SeverityError, `polygon`, invalidpolygonclosed, id),
+		}},
+		5: {input: Entry{Polygon: "47.202 5.755 95.183 5.755 95.183 15.253 47.202 15.253 47.202 5.755"}, expected: []Finding{
+			finding(SeverityError, `polygon`, invalidgeorssrange, `polygon`, id),
+		}},
+		6: {input: Entry{Polygon: "47.202 5.755 55.183 north 55.183 15.253 47.202 15.253 47.202 5.755"}, expected: []Finding{
+			finding(SeverityError, `polygon`, invalidgeorss, `polygon`, id),
+		}},
+		// the Netherlands as longitude latitude pairs
+		7: {input: Entry{Polygon: "3.2 50.75 3.2 53.7 7.22 53.7 7.22 50.75 3.2 50.75"}, expected: []Finding{
+			finding(SeverityWarning, `polygon`, warninggeorssswapped, `polygon`, id),
+		}},
+		// outside of Europe and not swapped
+		8: {input: Entry{Polygon: "-33.9 18.4 -33.8 18.4 -33.8 18.5 -33.9 18.5 -33.9 18.4"}, expected: nil},
+		9: {input: Entry{Point: "52.155172 5.387204 52.2 5.4", Line: "52.1"}, expected: []Finding{
+			finding(SeverityError, `line`, invalidgeorssodd, `line`, id),
+			finding(SeverityError, `line`, invalidgeorsspositions, `line`, id, `at least 2`),
+			finding(SeverityError, `point`, invalidgeorsspositions, `point`, id, `1`),
+		}},
+		10: {input: Entry{Point: "5.387204 52.155172", Box: "55.183 5.755 47.202 15.253"}, expected: []Finding{
+			finding(SeverityWarning, `point`, warninggeorssswapped, `point`, id),
+			finding(SeverityError, `box`, invalidgeorsscorners, `box`, id),
+		}},
+		11: {input: Entry{Where: envelope("", "13565.4 306846.2", "278026.1")}, expected: []Finding{
+			finding(SeverityError, `where.envelope.srs_name`, invalidwheresrsname, id),
+			finding(SeverityError, `where.envelope`, invalidwherecorners, id),
+		}},
+		12: {input: Entry{Where: envelope("http://www.opengis.net/def/crs/EPSG/0/28992", "278026.1 306846.2", "13565.4 619315.3")}, expected: []Finding{
+			finding(SeverityError, `where.envelope`, invalidgeorsscorners, `where`, id),
+		}},
+	}
+
+	for k, test := range tests {
+		var report Report
+		test.input.ID = id
+		test.input.validGeoRSS(&report, `entry[0]`)
+		if !reflect.DeepEqual(report.Findings, test.expected) {
+			t.Errorf("test: %d, expected: %v \ngot: %v", k, test.expected, report.Findings)
+		}
+	}
+}
+
+func TestProcessFeedsGeoRSS(t *testing.T) {
+	input := Feeds{Feeds: []Feed{{
+		ID: "http://xyz.org/data/abc/waternetwork.xml",
+		Entry: []Entry{{
+			ID:    "http://xyz.org/data/abc/waternetwork_28992.gml",
+			Point: "52.155172 5.387204",
+			Box:   "50.75 3.2 53.7 7.22",
+			Where: &Where{Envelope: Envelope{SrsName: "http://www.opengis.net/def/crs/EPSG/0/28992", LowerCorner: "13565.4 306846.2", UpperCorner: "278026.1 619315.3"}},
+		}},
+	}}}
+
+	output, err := ProcessFeeds(input, Options{SkipData: true, Resolver: NewResolver(testOptions())})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := output[0].GenerateATOM()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`xmlns:georss="http://www.georss.org/georss"`,
+		`xmlns:gml="http://www.opengis.net/gml"`,
+		`<georss:box>50.75 3.2 53.7 7.22</georss:box>`,
+		`<georss:point>52.155172 5.387204</georss:point>`,
+		"<georss:where>\n   <gml:Envelope srsName=\"http://www.opengis.net/def/crs/EPSG/0/28992\">\n    <gml:lowerCorner>13565.4 306846.2</gml:lowerCorner>\n    <gml:upperCorner>278026.1 619315.3</gml:upperCorner>\n   </gml:Envelope>\n  </georss:where>",
+	} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("expected %s in: %s", expected, b)
+		}
+	}
+}
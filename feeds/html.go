@@ -0,0 +1,175 @@
+package feeds
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+//go:embed templates/feed.html
+var templates embed.FS
+
+// htmlFuncs are the functions that are available in the HTML templates, next to the Feed as data
+var htmlFuncs = template.FuncMap{
+	// crs returns the CRS categories
+	`crs`: func(categories []Category) []Category {
+		var crs []Category
+		for _, c := range categories {
+			if isCRSCategory(c) {
+				crs = append(crs, c)
+			}
+		}
+		return crs
+	},
+	// downloads returns the 'alternate' and 'section' links, a link without rel is an 'alternate' link
+	`downloads`: func(links []Link) []Link {
+		var downloads []Link
+		for _, l := range links {
+			if l.Rel == `` || isDownloadLink(l) {
+				downloads = append(downloads, l)
+			}
+		}
+		return downloads
+	},
+	`size`:   humanSize,
+	`map`:    mapURL,
+	`xmlURL`: (*Feed).xmlURL,
+}
+
+// defaultTemplate renders a Feed as a HTML page
+var defaultTemplate = template.Must(template.New(`feed.html`).Funcs(htmlFuncs).ParseFS(templates, `templates/feed.html`))
+
+// LoadHTMLTemplate parses a HTML template file, which has the Feed as data and the same functions as the default template:
+// crs, downloads, size, map and xmlURL
+func LoadHTMLTemplate(filename string) (*template.Template, error) {
+	t, err := template.New(path.Base(filename)).Funcs(htmlFuncs).ParseFiles(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the HTML template %s: %w", filename, err)
+	}
+	return t, nil
+}
+
+// GenerateHTML renders the Feed as a human readable HTML page with the default template
+func (f *Feed) GenerateHTML() ([]byte, error) {
+	return f.GenerateHTMLWith(defaultTemplate)
+}
+
+// GenerateHTMLWith renders the Feed as a human readable HTML page with the given template
+func (f *Feed) GenerateHTMLWith(t *template.Template) ([]byte, error) {
+	var b bytes.Buffer
+	if err := t.Execute(&b, f); err != nil {
+		return nil, &ProcessError{FeedID: f.ID, Err: err}
+	}
+	return b.Bytes(), nil
+}
+
+// WriteHTML writes the HTML page of the Feed to file, a nil template is the default template
+//
+//nolint:gosec
+func (f *Feed) WriteHTML(filename string, t *template.Template) error {
+	if t == nil {
+		t = defaultTemplate
+	}
+	b, err := f.GenerateHTMLWith(t)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, b, 0777); err != nil {
+		return &ProcessError{FeedID: f.ID, Err: fmt.Errorf("could not write to file %s: %w", filename, err)}
+	}
+	return nil
+}
+
+// HTMLFileName returns the file name of the HTML page, which is the last part of the href of the
+// 'alternate' text/html link in the language of the Feed, or the file name of the Feed with a .html extension
+func (f *Feed) HTMLFileName() (string, error) {
+	for _, l := range f.Links(alternate) {
+		if !strings.HasPrefix(l.Type, `text/html`) || (l.Hreflang != nil && f.Lang != nil && *l.Hreflang != *f.Lang) {
+			continue
+		}
+		if u, err := url.Parse(l.Href); err == nil && path.Base(u.Path) != `/` && path.Base(u.Path) != `.` {
+			return path.Base(u.Path), nil
+		}
+	}
+	filename, err := f.GetFileName()
+	if err != nil {
+		return ``, err
+	}
+	return strings.TrimSuffix(filename, path.Ext(filename)) + `.html`, nil
+}
+
+// xmlURL returns the href of the ATOM feed, the 'self' link or the id
+func (f *Feed) xmlURL() string {
+	if links := f.Links(self); len(links) > 0 {
+		return links[0].Href
+	}
+	return f.ID
+}
+
+// GenerateJSON encodes the Feed as JSON, with the names of the configuration
+func (f *Feed) GenerateJSON() ([]byte, error) {
+	b, err := json.MarshalIndent(f, "", " ")
+	if err != nil {
+		return nil, &ProcessError{FeedID: f.ID, Err: err}
+	}
+	return b, nil
+}
+
+// humanSize formats a length in bytes, e.g. 34987 as 35.0 kB
+func humanSize(length string) string {
+	n, err := strconv.ParseFloat(length, 64)
+	if err != nil {
+		return length
+	}
+	units := []string{`B`, `kB`, `MB`, `GB`, `TB`}
+	i := 0
+	for n >= 1000 && i < len(units)-1 {
+		n /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", n, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
+
+// mapURL returns the URL of an OpenStreetMap preview of the extent of an entry, the georss polygon or box
+// or the union of the bbox of its links, it is empty when the entry has no extent
+func mapURL(e Entry) string {
+	b := extent{minLat: math.Inf(1), minLon: math.Inf(1), maxLat: math.Inf(-1), maxLon: math.Inf(-1)}
+	add := func(value string) {
+		numbers, ok := parseNumbers(value)
+		if !ok {
+			return
+		}
+		for i := 0; i+1 < len(numbers); i += 2 {
+			b = b.union(extent{minLat: numbers[i], minLon: numbers[i+1], maxLat: numbers[i], maxLon: numbers[i+1]})
+		}
+	}
+
+	switch {
+	case e.Polygon != ``:
+		add(e.Polygon)
+	case e.Box != ``:
+		add(e.Box)
+	default:
+		for _, l := range e.Link {
+			if l.Bbox != nil {
+				add(*l.Bbox)
+			}
+		}
+	}
+	if b.minLat > b.maxLat {
+		return ``
+	}
+	bbox := strings.Join([]string{coordinate(b.minLon), coordinate(b.minLat), coordinate(b.maxLon), coordinate(b.maxLat)}, `,`)
+	return `https://www.openstreetmap.org/export/embed.html?bbox=` + bbox + `&layer=mapnik`
+}
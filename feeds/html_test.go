@@ -0,0 +1,155 @@
+package feeds
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFeedGenerateHTMLAndJSON(t *testing.T) {
+	f := Feed{
+		ID:    "http://xyz.org/data/abc/waternetwork.xml",
+		Title: "Water network <ABC>",
+		Lang:  sp("en"),
+		Entry: []Entry{{
+			ID:                           "http://xyz.org/data/abc/waternetwork_25832.gml",
+			SpatialDatasetIdentifierCode: sp("wn_id1"),
+			Link:                         []Link{{Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Data: sp("./data/waternetwork_25832.gml")}},
+		}},
+	}
+
+	html, err := f.GenerateHTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{`<html lang="en">`, `<h1>Water network &lt;ABC&gt;</h1>`, `<a href="http://xyz.org/data/abc/waternetwork_25832.gml">`} {
+		if !strings.Contains(string(html), expected) {
+			t.Errorf("expected %s in: %s", expected, html)
+		}
+	}
+
+	json, err := f.GenerateJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{`"spatial_dataset_identifier_code": "wn_id1"`, `"lang": "en"`} {
+		if !strings.Contains(string(json), expected) {
+			t.Errorf("expected %s in: %s", expected, json)
+		}
+	}
+	// the data and the namespaces are not part of the JSON
+	if strings.Contains(string(json), `./data`) || strings.Contains(string(json), `xmlns`) {
+		t.Errorf("unexpected data or namespace in: %s", json)
+	}
+}
+
+func TestFeedGenerateHTMLEntries(t *testing.T) {
+	f := Feed{
+		ID:      "http://xyz.org/data/abc/waternetwork.xml",
+		Title:   "Water network",
+		Lang:    sp("en"),
+		Updated: sp("2021-01-01T00:00:00Z"),
+		Link:    []Link{Self(Link{Href: "http://xyz.org/data/abc/waternetwork.xml"})},
+		Entry: []Entry{{
+			ID:       "http://xyz.org/data/abc/waternetwork_28992.gml",
+			Title:    "Water network in RD",
+			Polygon:  "50.5 3.2 53.7 3.2 53.7 7.3 50.5 7.3 50.5 3.2",
+			Category: []Category{{Term: "http://www.opengis.net/def/crs/EPSG/0/28992", Label: "Amersfoort / RD New"}, {Term: "water"}},
+			Link: []Link{
+				{Rel: alternate, Href: "http://xyz.org/data/abc/waternetwork_28992.gml", Type: "application/gml+xml", Length: "34987"},
+				{Rel: describedby, Href: "http://xyz.org/metadata/abc"},
+			},
+		}},
+	}
+
+	html, err := f.GenerateHTML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<link rel="alternate" type="application/atom+xml" href="http://xyz.org/data/abc/waternetwork.xml">`,
+		`<h2>Water network in RD</h2>`,
+		`<a href="http://www.opengis.net/def/crs/EPSG/0/28992">Amersfoort / RD New</a>`,
+		`<td>application/gml&#43;xml</td><td>35.0 kB</td>`,
+		`<iframe src="https://www.openstreetmap.org/export/embed.html?bbox=3.2,50.5,7.3,53.7&amp;layer=mapnik"`,
+	} {
+		if !strings.Contains(string(html), expected) {
+			t.Errorf("expected %s in: %s", expected, html)
+		}
+	}
+	for _, unexpected := range []string{`http://xyz.org/metadata/abc`, `href="water"`} {
+		if strings.Contains(string(html), unexpected) {
+			t.Errorf("unexpected %s in: %s", unexpected, html)
+		}
+	}
+}
+
+func TestLoadHTMLTemplate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "custom.html")
+	if err := os.WriteFile(filename, []byte(`<h1>{{ .Title }}</h1>{{ range .Entry }}{{ map . }}{{ end }}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := LoadHTMLTemplate(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := Feed{Title: "Water network", Entry: []Entry{{Box: "50.5 3.2 53.7 7.3"}}}
+	html, err := f.GenerateHTMLWith(tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<h1>Water network</h1>https://www.openstreetmap.org/export/embed.html?bbox=3.2,50.5,7.3,53.7&amp;layer=mapnik`
+	if string(html) != expected {
+		t.Errorf("expected %s, got %s", expected, html)
+	}
+
+	if err := os.WriteFile(filename, []byte(`{{ .Title `), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadHTMLTemplate(filename); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestHTMLFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     Feed
+		expected string
+	}{
+		{
+			name: "alternate html link in the feed language",
+			feed: Feed{ID: "http://xyz.org/download/index.xml", Lang: sp("de"), Link: []Link{
+				{Rel: alternate, Type: "text/html", Href: "http://xyz.org/download/index.html", Hreflang: sp("en")},
+				{Rel: alternate, Type: "text/html", Href: "http://xyz.org/download/index.de.html", Hreflang: sp("de")},
+			}},
+			expected: "index.de.html",
+		},
+		{
+			name:     "no alternate html link",
+			feed:     Feed{ID: "http://xyz.org/data/abc/waternetwork.xml", Lang: sp("en")},
+			expected: "waternetwork.html",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, err := tt.feed.HTMLFileName()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if name != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, name)
+			}
+		})
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := map[string]string{"": "", "512": "512 B", "34987": "35.0 kB", "1200000000": "1.2 GB", "n/a": "n/a"}
+	for length, expected := range tests {
+		if size := humanSize(length); size != expected {
+			t.Errorf("expected %s for %s, got %s", expected, length, size)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package feeds
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// langPlaceholder is replaced by the language in all values of a language variant, e.g. in the `id`
+const langPlaceholder = `{lang}`
+
+// the texts that accept a map of language to text
+var (
+	feedTexts  = []string{`title`, `subtitle`, `rights`}
+	entryTexts = []string{`title`, `summary`, `rights`}
+	linkTexts  = []string{`title`}
+)
+
+// UnmarshalYAML decodes the Feeds, a feed with texts in multiple languages is expanded
+// into one feed per language, the variants link to each other with 'alternate' links
+func (fs *Feeds) UnmarshalYAML(value *yaml.Node) error {
+	if err := checkDefaults(mappingValue(value, `defaults`)); err != nil {
+		return err
+	}
+
+	var variants [][]int
+	if feeds := mappingValue(value, `feeds`); feeds != nil && feeds.Kind == yaml.SequenceNode {
+		var expanded []*yaml.Node
+		for _, feed := range feeds.Content {
+			localized, err := localize(feed)
+			if err != nil {
+				return err
+			}
+			if len(localized) > 1 {
+				indexes := make([]int, len(localized))
+				for i := range localized {
+					indexes[i] = len(expanded) + i
+				}
+				variants = append(variants, indexes)
+			}
+			expanded = append(expanded, localized...)
+		}
+		feeds.Content = expanded
+	}
+
+	// plain has the fields of Feeds, but not this method
+	type plain Feeds
+	if err := value.Decode((*plain)(fs)); err != nil {
+		return err
+	}
+	for _, indexes := range variants {
+		fs.linkVariants(indexes)
+	}
+	return nil
+}
+
+// linkVariants adds an 'alternate' link to every other language variant of a feed, unless it is already linked
+func (fs *Feeds) linkVariants(indexes []int) {
+	for _, i := range indexes {
+		f := &fs.Feeds[i]
+		for _, j := range indexes {
+			other := fs.Feeds[j]
+			if i == j || slices.ContainsFunc(f.Link, func(l Link) bool { return l.Href == other.ID }) {
+				continue
+			}
+			lang := *other.Lang
+			f.Link = append(f.Link, Link{Href: other.ID, Rel: alternate, Type: `application/atom+xml`, Hreflang: &lang, Title: other.Title})
+		}
+	}
+}
+
+// localize returns a variant of the feed for every language of its texts, in which
+// the texts are replaced by the text in that language and {lang} by the language,
+// the `lang` of the feed comes first, a feed without translated texts is returned as is
+func localize(feed *yaml.Node) ([]*yaml.Node, error) {
+	if feed.Kind != yaml.MappingNode {
+		return []*yaml.Node{feed}, nil
+	}
+	var languages []string
+	_ = texts(feed, func(_ string, text *yaml.Node) error {
+		if text.Kind != yaml.MappingNode {
+			return nil
+		}
+		for i := 0; i+1 < len(text.Content); i += 2 {
+			if lang := text.Content[i].Value; !slices.Contains(languages, lang) {
+				languages = append(languages, lang)
+			}
+		}
+		return nil
+	})
+	if len(languages) == 0 {
+		return []*yaml.Node{feed}, nil
+	}
+	if lang := mappingValue(feed, `lang`); lang != nil {
+		if i := slices.Index(languages, lang.Value); i > 0 {
+			languages = slices.Insert(slices.Delete(languages, i, i+1), 0, lang.Value)
+		}
+	}
+	if id := mappingValue(feed, `id`); id == nil || !strings.Contains(id.Value, langPlaceholder) {
+		return nil, fmt.Errorf("line %d: the 'id' of a feed with texts in multiple languages needs a %s placeholder", feed.Line, langPlaceholder)
+	}
+
+	variants := make([]*yaml.Node, 0, len(languages))
+	for _, lang := range languages {
+		variant := cloneNode(feed)
+		err := texts(variant, func(key string, text *yaml.Node) error {
+			if text.Kind != yaml.MappingNode {
+				return nil
+			}
+			translation := mappingValue(text, lang)
+			if translation == nil {
+				return fmt.Errorf("line %d: '%s' has no text in language %s", text.Line, key, lang)
+			}
+			*text = *translation
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		replaceLang(variant, lang)
+		if l := mappingValue(variant, `lang`); l != nil {
+			l.Value = lang
+		} else {
+			variant.Content = append(variant.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: `!!str`, Value: `lang`},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: `!!str`, Value: lang})
+		}
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// texts calls fn for the value of every text of the feed, its links, entries and generators
+func texts(feed *yaml.Node, fn func(key string, text *yaml.Node) error) error {
+	visit := func(node *yaml.Node, keys []string) error {
+		for _, key := range keys {
+			if text := mappingValue(node, key); text != nil {
+				if err := fn(key, text); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	visitAll := func(node *yaml.Node, key string, keys []string) error {
+		sequence := mappingValue(node, key)
+		if sequence == nil {
+			return nil
+		}
+		for _, item := range sequence.Content {
+			if err := visit(item, keys); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := visit(feed, feedTexts); err != nil {
+		return err
+	}
+	for _, predefined := range []string{self, describedby, search, up} {
+		if l := mappingValue(feed, predefined); l != nil {
+			if err := visit(l, linkTexts); err != nil {
+				return err
+			}
+		}
+	}
+	if err := visitAll(feed, `link`, linkTexts); err != nil {
+		return err
+	}
+	if err := visitAll(feed, `generate`, linkTexts); err != nil {
+		return err
+	}
+	if entries := mappingValue(feed, `entry`); entries != nil {
+		for _, entry := range entries.Content {
+			if err := visit(entry, entryTexts); err != nil {
+				return err
+			}
+			if err := visitAll(entry, `link`, linkTexts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value of a key of a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// cloneNode returns a deep copy of a node, aliases are not copied
+func cloneNode(node *yaml.Node) *yaml.Node {
+	clone := *node
+	clone.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		clone.Content[i] = cloneNode(child)
+	}
+	return &clone
+}
+
+// replaceLang replaces {lang} by the language in all scalar values
+func replaceLang(node *yaml.Node, lang string) {
+	if node.Kind == yaml.ScalarNode {
+		node.Value = strings.ReplaceAll(node.Value, langPlaceholder, lang)
+	}
+	for _, child := range node.Content {
+		replaceLang(child, lang)
+	}
+}
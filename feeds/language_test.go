@@ -0,0 +1,113 @@
+package feeds
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestFeedsUnmarshalYAMLLanguages(t *testing.T) {
+	config := `
+feeds:
+ - id: "http://xyz.org/download/{lang}.xml"
+   lang: de
+   title:
+     en: "Download service"
+     de: "Downloaddienst"
+   rights: "Copyright XYZ"
+   self:
+     href: "http://xyz.org/download/{lang}.xml"
+     title:
+       en: "This document"
+       de: "Dieses Dokument"
+   entry:
+    - id: "http://xyz.org/data/abc/{lang}.xml"
+      title: "ABC"
+      summary:
+        en: "Water network"
+        de: "Gewässernetz"
+      link:
+       - rel: alternate
+         href: "http://xyz.org/data/abc/{lang}.xml"
+ - id: "http://xyz.org/data/abc/waternetwork.xml"
+   lang: en
+   title: "Water network"
+`
+	var fs Feeds
+	if err := yaml.Unmarshal([]byte(config), &fs); err != nil {
+		t.Fatal(err)
+	}
+	if len(fs.Feeds) != 3 {
+		t.Fatalf("expected 3 feeds, got %d", len(fs.Feeds))
+	}
+
+	de, en := fs.Feeds[0], fs.Feeds[1]
+	expected := []struct {
+		feed                                Feed
+		id, lang, title, self, summary, alt string
+	}{
+		{de, "http://xyz.org/download/de.xml", "de", "Downloaddienst", "Dieses Dokument", "Gewässernetz", "http://xyz.org/download/en.xml"},
+		{en, "http://xyz.org/download/en.xml", "en", "Download service", "This document", "Water network", "http://xyz.org/download/de.xml"},
+	}
+	for _, e := range expected {
+		f := e.feed
+		if f.ID != e.id || *f.Lang != e.lang || f.Title != e.title || f.Rights != "Copyright XYZ" || f.Self.Title != e.self {
+			t.Errorf("unexpected variant %s: %+v", e.lang, f)
+		}
+		if f.Entry[0].Summary != e.summary || f.Entry[0].Link[0].Href != "http://xyz.org/data/abc/"+e.lang+".xml" {
+			t.Errorf("unexpected entry of variant %s: %+v", e.lang, f.Entry[0])
+		}
+		other := map[string]string{"de": "en", "en": "de"}[e.lang]
+		alternates := []Link{{Href: e.alt, Rel: alternate, Type: "application/atom+xml", Hreflang: sp(other), Title: map[string]string{"de": "Download service", "en": "Downloaddienst"}[e.lang]}}
+		if !reflect.DeepEqual(f.Link, alternates) {
+			t.Errorf("expected alternate links %+v, got %+v", alternates, f.Link)
+		}
+	}
+	if fs.Feeds[2].Title != "Water network" || len(fs.Feeds[2].Link) != 0 {
+		t.Errorf("unexpected feed without translations: %+v", fs.Feeds[2])
+	}
+}
+
+func TestFeedsUnmarshalYAMLLanguagesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			name: "missing translation",
+			config: `
+feeds:
+ - id: "http://xyz.org/download/{lang}.xml"
+   title:
+     en: "Download service"
+     de: "Downloaddienst"
+   subtitle:
+     en: "Hydrography"
+`,
+			expected: "line 8: 'subtitle' has no text in language de",
+		},
+		{
+			name: "id without placeholder",
+			config: `
+feeds:
+ - id: "http://xyz.org/download/en.xml"
+   title:
+     en: "Download service"
+     de: "Downloaddienst"
+`,
+			expected: "line 3: the 'id' of a feed with texts in multiple languages needs a {lang} placeholder",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fs Feeds
+			err := yaml.Unmarshal([]byte(tt.config), &fs)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected error %s, got %v", tt.expected, err)
+			}
+		})
+	}
+}
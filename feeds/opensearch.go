@@ -0,0 +1,248 @@
+package feeds
+
+import (
+	"cmp"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	opensearchNamespace = `http://a9.com/-/spec/opensearch/1.1/`
+	inspireDlsNamespace = `http://inspire.ec.europa.eu/schemas/inspire_dls/1.0`
+
+	// the maximum lengths of the OpenSearch 1.1 specification
+	maxShortName   = 16
+	maxLongName    = 48
+	maxDescription = 1024
+)
+
+// OpenSearchDescription is the OpenSearch description document of a service feed, which is the target of its 'search' link
+// TG Requirement 8 - Technical Guidance Download Services v3.1
+//
+//nolint:tagliatelle
+type OpenSearchDescription struct {
+	XMLName     xml.Name          `xml:"OpenSearchDescription"`
+	Xmlns       string            `xml:"xmlns,attr"`
+	InspireDls  string            `xml:"xmlns:inspire_dls,attr"`
+	Lang        string            `xml:"xml:lang,attr,omitempty"`
+	ShortName   string            `xml:"ShortName"`
+	Description string            `xml:"Description"`
+	URL         []OpenSearchURL   `xml:"Url"`
+	Contact     string            `xml:"Contact,omitempty"`
+	Tags        string            `xml:"Tags,omitempty"`
+	LongName    string            `xml:"LongName,omitempty"`
+	Query       []OpenSearchQuery `xml:"Query"`
+	Developer   string            `xml:"Developer,omitempty"`
+	Language    []string          `xml:"Language"`
+}
+
+// OpenSearchURL is a URL template of an OpenSearch description
+type OpenSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Rel      string `xml:"rel,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// OpenSearchQuery is an example query of an OpenSearch description, for a single dataset, CRS and language
+//
+//nolint:tagliatelle
+type OpenSearchQuery struct {
+	Role      string `xml:"role,attr"`
+	Namespace string `xml:"inspire_dls:spatial_dataset_identifier_namespace,attr,omitempty"`
+	Code      string `xml:"inspire_dls:spatial_dataset_identifier_code,attr"`
+	CRS       string `xml:"inspire_dls:crs,attr,omitempty"`
+	Language  string `xml:"language,attr,omitempty"`
+	Title     string `xml:"title,attr,omitempty"`
+	Count     int    `xml:"count,attr,omitempty"`
+}
+
+// OpenSearchDescription returns the OpenSearch description of a processed service feed,
+// the URL templates of the Describe and Get Spatial Dataset operations are based on the 'id' of the feed
+// and the example queries cover every spatial_dataset_identifier_code, CRS and language of the feeds
+func (f *Feed) OpenSearchDescription(feeds []Feed) OpenSearchDescription {
+	index := Feeds{Feeds: feeds}.index()
+	languages := f.languages(index)
+	d := OpenSearchDescription{
+		Xmlns:       opensearchNamespace,
+		InspireDls:  inspireDlsNamespace,
+		Lang:        languages[0],
+		ShortName:   truncate(f.Title, maxShortName),
+		Description: truncate(cmp.Or(f.Subtitle, f.Title), maxDescription),
+		Contact:     f.Author.Email,
+		Tags:        `Search Download`,
+		LongName:    truncate(f.Title, maxLongName),
+		Developer:   f.Author.Name,
+		Language:    languages,
+	}
+	for _, l := range f.Links(search) {
+		d.URL = append(d.URL, OpenSearchURL{Type: `application/opensearchdescription+xml`, Rel: self, Template: l.Href})
+	}
+
+	identifier := `spatial_dataset_identifier_code={inspire_dls:spatial_dataset_identifier_code?}` +
+		`&spatial_dataset_identifier_namespace={inspire_dls:spatial_dataset_identifier_namespace?}`
+	d.URL = append(d.URL,
+		OpenSearchURL{Type: `application/atom+xml`, Rel: `results`, Template: withQuery(f.ID, `q={searchTerms}`)},
+		// Describe Spatial Dataset returns the dataset feed
+		OpenSearchURL{Type: `application/atom+xml`, Rel: describedby, Template: withQuery(f.ID, identifier+`&language={language}&q={searchTerms?}`)},
+	)
+
+	var types []string
+	for _, entry := range f.Entry {
+		var datasets []Feed
+		for _, l := range entry.Link {
+			if i, ok := index[l.Href]; ok && isFeedLink(l) {
+				datasets = append(datasets, feeds[i])
+			}
+		}
+		for _, dataset := range datasets {
+			for _, datasetEntry := range dataset.Entry {
+				for _, l := range datasetEntry.Link {
+					if isDownloadLink(l) && l.Type != `` && !slices.Contains(types, l.Type) {
+						types = append(types, l.Type)
+					}
+				}
+			}
+		}
+		d.Query = append(d.Query, entry.queries(datasets, languages)...)
+	}
+
+	// Get Spatial Dataset returns the download, one template per media type, the crs is mandatory,
+	// because without it the same URL is the Describe Spatial Dataset operation
+	for _, t := range types {
+		d.URL = append(d.URL, OpenSearchURL{Type: t, Rel: `results`,
+			Template: withQuery(f.ID, identifier+`&crs={inspire_dls:crs}&language={language}&q={searchTerms?}`)})
+	}
+	return d
+}
+
+// queries returns the example queries of a service feed entry, for every CRS of the entry and its dataset feeds and every language
+func (e Entry) queries(datasets []Feed, languages []string) []OpenSearchQuery {
+	if e.SpatialDatasetIdentifierCode == nil {
+		return nil
+	}
+	var namespace string
+	if e.SpatialDatasetIdentifierNamespace != nil {
+		namespace = *e.SpatialDatasetIdentifierNamespace
+	}
+
+	var crs []string
+	categories := slices.Clone(e.Category)
+	for _, dataset := range datasets {
+		for _, datasetEntry := range dataset.Entry {
+			categories = append(categories, datasetEntry.Category...)
+		}
+	}
+	for _, c := range categories {
+		if isCRSCategory(c) && !slices.Contains(crs, c.Term) {
+			crs = append(crs, c.Term)
+		}
+	}
+	if len(crs) == 0 {
+		crs = []string{``}
+	}
+
+	var queries []OpenSearchQuery
+	for _, term := range crs {
+		for _, language := range languages {
+			queries = append(queries, OpenSearchQuery{
+				Role:      `example`,
+				Namespace: namespace,
+				Code:      *e.SpatialDatasetIdentifierCode,
+				CRS:       term,
+				Language:  language,
+				Title:     e.Title,
+				Count:     1,
+			})
+		}
+	}
+	return queries
+}
+
+// languages returns the language of the feed followed by the hreflang of its 'alternate' links to other feeds, which are its translations
+func (f *Feed) languages(index map[string]int) []string {
+	languages := []string{defaultlang}
+	if f.Lang != nil {
+		languages[0] = *f.Lang
+	}
+	for _, l := range f.Links(alternate) {
+		if _, ok := index[l.Href]; !ok {
+			continue
+		}
+		if l.Hreflang != nil && !slices.Contains(languages, *l.Hreflang) {
+			languages = append(languages, *l.Hreflang)
+		}
+	}
+	return languages
+}
+
+// OpenSearchDescriptions returns the OpenSearch description of every 'search' href of the processed service feeds,
+// the language variants of a service feed share their 'search' href, so the description of the first
+// variant is used, which lists the languages of all variants
+func OpenSearchDescriptions(feeds []Feed) map[string]OpenSearchDescription {
+	descriptions := make(map[string]OpenSearchDescription)
+	for _, f := range feeds {
+		if f.Kind() != ServiceFeed {
+			continue
+		}
+		for _, l := range f.Links(search) {
+			if _, ok := descriptions[l.Href]; !ok {
+				descriptions[l.Href] = f.OpenSearchDescription(feeds)
+			}
+		}
+	}
+	return descriptions
+}
+
+// OpenSearchFileName returns the file name of the OpenSearch description with the href, the last part of its path
+func OpenSearchFileName(href string) (string, bool) {
+	u, err := url.Parse(href)
+	if err != nil || path.Base(u.Path) == `/` || path.Base(u.Path) == `.` {
+		return ``, false
+	}
+	return path.Base(u.Path), true
+}
+
+// GenerateXML builds the OpenSearch description document
+func (d OpenSearchDescription) GenerateXML() ([]byte, error) {
+	b, err := xml.MarshalIndent(d, "", " ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// WriteXML writes the OpenSearch description document to file
+//
+//nolint:gosec
+func (d OpenSearchDescription) WriteXML(filename string) error {
+	b, err := d.GenerateXML()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, b, 0777); err != nil {
+		return fmt.Errorf("could not write to file %s: %w", filename, err)
+	}
+	return nil
+}
+
+// withQuery appends a query to a URL, which may already have one
+func withQuery(uri, query string) string {
+	if strings.Contains(uri, `?`) {
+		return uri + `&` + query
+	}
+	return uri + `?` + query
+}
+
+// truncate shortens a value to at most n characters
+func truncate(value string, n int) string {
+	runes := []rune(value)
+	if len(runes) <= n {
+		return value
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,117 @@
+package feeds
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFeedOpenSearchDescription(t *testing.T) {
+	input := Feeds{Feeds: []Feed{
+		{
+			ID:       "http://xyz.org/download/en.xml",
+			Title:    "XYZ Example INSPIRE Download Service",
+			Subtitle: "INSPIRE Download Service of organisation XYZ",
+			Author:   Author{Name: "John Doe", Email: "doe@xyz.org"},
+			Search:   &Link{Href: "http://xyz.org/search/opensearchdescription.xml"},
+			Link: []Link{
+				{Href: "http://xyz.org/download/de.xml", Rel: "alternate", Type: "application/atom+xml", Hreflang: sp("de")},
+				{Href: "http://xyz.org/download/fr.xml", Rel: "alternate", Type: "application/atom+xml", Hreflang: sp("fr")},
+			},
+			Entry: []Entry{{
+				ID:                                "http://xyz.org/data/abc/waternetwork.xml",
+				Title:                             "Water network ABC",
+				SpatialDatasetIdentifierCode:      sp("wn_id1"),
+				SpatialDatasetIdentifierNamespace: sp("http://xyz.org/"),
+				Link:                              []Link{{Href: "http://xyz.org/data/abc/waternetwork.xml", Rel: "alternate", Type: "application/atom+xml"}},
+			}},
+		},
+		{
+			ID:     "http://xyz.org/download/de.xml",
+			Lang:   sp("de"),
+			Title:  "XYZ Beispiel INSPIRE Downloaddienst",
+			Search: &Link{Href: "http://xyz.org/search/opensearchdescription.xml"},
+			Link:   []Link{{Href: "http://xyz.org/download/en.xml", Rel: "alternate", Type: "application/atom+xml", Hreflang: sp("en")}},
+			Entry: []Entry{{
+				ID:                                "http://xyz.org/data/abc/waternetwork.xml",
+				SpatialDatasetIdentifierCode:      sp("wn_id1"),
+				SpatialDatasetIdentifierNamespace: sp("http://xyz.org/"),
+				Link:                              []Link{{Href: "http://xyz.org/data/abc/waternetwork.xml", Rel: "alternate", Type: "application/atom+xml"}},
+			}},
+		},
+		{
+			ID: "http://xyz.org/data/abc/waternetwork.xml",
+			Entry: []Entry{
+				{ID: "http://xyz.org/data/abc/waternetwork_25832.gml", CRS: []int{25832},
+					Link: []Link{{Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Rel: "alternate", Type: "application/gml+xml"}}},
+				{ID: "http://xyz.org/data/abc/waternetwork_4258.zip", CRS: []int{4258},
+					Link: []Link{{Href: "http://xyz.org/data/abc/waternetwork_4258.zip", Rel: "alternate", Type: "application/x-shapefile"}}},
+			},
+		},
+	}}
+	processed, err := ProcessFeeds(input, Options{SkipData: true, Resolver: NewResolver(testOptions())})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := processed[0].OpenSearchDescription(processed)
+	if d.ShortName != "XYZ Example INSP" || d.LongName != "XYZ Example INSPIRE Download Service" || d.Contact != "doe@xyz.org" {
+		t.Errorf("unexpected names: %s, %s, %s", d.ShortName, d.LongName, d.Contact)
+	}
+	// the fr translation is not configured, so it is not listed
+	if !reflect.DeepEqual(d.Language, []string{"en", "de"}) {
+		t.Errorf("expected the languages of the feed and its translations, got: %v", d.Language)
+	}
+
+	var urls []string
+	for _, u := range d.URL {
+		urls = append(urls, u.Rel+" "+u.Type)
+	}
+	expectedURLs := []string{
+		"self application/opensearchdescription+xml",
+		"results application/atom+xml",
+		"describedby application/atom+xml",
+		"results application/gml+xml",
+		"results application/x-shapefile",
+	}
+	if !reflect.DeepEqual(urls, expectedURLs) {
+		t.Errorf("expected urls: %v \ngot: %v", expectedURLs, urls)
+	}
+	if !strings.HasPrefix(d.URL[3].Template, "http://xyz.org/download/en.xml?spatial_dataset_identifier_code={inspire_dls:spatial_dataset_identifier_code?}") {
+		t.Errorf("unexpected template: %s", d.URL[3].Template)
+	}
+	// the crs is what distinguishes Get Spatial Dataset from Describe Spatial Dataset
+	if !strings.Contains(d.URL[3].Template, "&crs={inspire_dls:crs}&") {
+		t.Errorf("expected a mandatory crs in the Get Spatial Dataset template: %s", d.URL[3].Template)
+	}
+
+	var queries []string
+	for _, q := range d.Query {
+		queries = append(queries, q.Namespace+q.Code+" "+q.CRS+" "+q.Language)
+	}
+	expectedQueries := []string{
+		"http://xyz.org/wn_id1 http://www.opengis.net/def/crs/EPSG/0/25832 en",
+		"http://xyz.org/wn_id1 http://www.opengis.net/def/crs/EPSG/0/25832 de",
+		"http://xyz.org/wn_id1 http://www.opengis.net/def/crs/EPSG/0/4258 en",
+		"http://xyz.org/wn_id1 http://www.opengis.net/def/crs/EPSG/0/4258 de",
+	}
+	if !reflect.DeepEqual(queries, expectedQueries) {
+		t.Errorf("expected queries: %v \ngot: %v", expectedQueries, queries)
+	}
+
+	// the language variants share one description, which is the one of the first variant
+	descriptions := OpenSearchDescriptions(processed)
+	if len(descriptions) != 1 || !reflect.DeepEqual(descriptions["http://xyz.org/search/opensearchdescription.xml"], d) {
+		t.Errorf("expected one description for both language variants, got: %v", descriptions)
+	}
+	if name, ok := OpenSearchFileName("http://xyz.org/search/opensearchdescription.xml"); !ok || name != "opensearchdescription.xml" {
+		t.Errorf("expected opensearchdescription.xml, got: %s", name)
+	}
+	if _, ok := OpenSearchFileName("http://xyz.org/"); ok {
+		t.Errorf("expected no file name for a href without a path")
+	}
+	b, err := d.GenerateXML()
+	if err != nil || !strings.Contains(string(b), `<Query role="example" inspire_dls:spatial_dataset_identifier_namespace="http://xyz.org/" inspire_dls:spatial_dataset_identifier_code="wn_id1"`) {
+		t.Errorf("unexpected document: %s, %v", b, err)
+	}
+}
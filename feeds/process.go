@@ -1,71 +1,331 @@
 package feeds
 
 import (
-	"net/http"
-
-	"github.com/imdario/mergo"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 )
 
-// ProcessFeed func
-func ProcessFeeds(fs Feeds) []Feed {
+// Options configures how the feeds are processed
+type Options struct {
+	// SkipData leaves the `data` links unresolved, so no HEAD requests are made
+	SkipData bool
+	// UpdatedFromData sets the 'updated' of entries without one to the newest Last-Modified of their `data` links,
+	// this is carried up to the dataset feeds and service feeds
+	UpdatedFromData bool
+	// ExtentFromData sets the 'bbox' of links without one and the 'polygon' of entries without one
+	// to the extent of their local GML and GeoPackage `data`
+	ExtentFromData bool
+	// Checksums computes the SHA-256 of the `data` links that have none, which downloads them, nil disables the checksums
+	Checksums *ChecksumOptions
+	// Resolver resolves the `data` links, when nil a Resolver with the DefaultResolverOptions
+	// and the S3 config of the Feeds is used
+	Resolver *Resolver
+}
+
+func (o Options) resolver(fs Feeds) *Resolver {
+	if o.Resolver == nil {
+		resolverOptions := DefaultResolverOptions()
+		resolverOptions.S3 = fs.S3
+		return NewResolver(resolverOptions)
+	}
+	return o.Resolver
+}
+
+// resolve resolves the `data` of the given feeds and, when enabled, reads their extents and computes their checksums
+func (o Options) resolve(resolver *Resolver, feeds []Feed) resolved {
+	data := Feeds{Feeds: feeds}
+
+	var r resolved
+	r.resources, r.errs = resolver.ResolveAll(context.Background(), data.dataURIs())
+	if o.ExtentFromData {
+		var errs map[string]error
+		r.extents, errs = resolver.extentAll(data.dataURIs())
+		r.unsupported = make(map[string]error)
+		for uri, err := range errs {
+			// data in an unsupported CRS is processed without an extent, which the validation reports
+			if errors.Is(err, errUnsupportedCRS) {
+				r.unsupported[uri] = err
+			} else {
+				r.errs[uri] = err
+			}
+		}
+	}
+	if o.Checksums == nil {
+		return r
+	}
+
+	// only the data of links without a checksum is downloaded
+	resources := make(map[string]Resource)
+	for _, uri := range data.checksumURIs() {
+		if resource, ok := r.resources[uri]; ok {
+			resources[uri] = resource
+		}
+	}
+	var errs map[string]error
+	r.checksums, errs = resolver.ChecksumAll(context.Background(), resources)
+	maps.Copy(r.errs, errs)
+	return r
+}
+
+// ProcessError is returned when a feed, or one of its entries or links, could not be processed
+type ProcessError struct {
+	FeedID  string
+	EntryID string
+	Href    string
+	Err     error
+}
+
+// Error returns the cause prefixed with the feed ID and, when available, the entry ID and link href
+func (e *ProcessError) Error() string {
+	parts := []string{`feed ` + e.FeedID}
+	if e.EntryID != `` {
+		parts = append(parts, `entry `+e.EntryID)
+	}
+	if e.Href != `` {
+		parts = append(parts, `link `+e.Href)
+	}
+	return fmt.Sprintf("%s: %v", strings.Join(parts, `, `), e.Err)
+}
+
+// Unwrap returns the cause of the ProcessError
+func (e *ProcessError) Unwrap() error {
+	return e.Err
+}
+
+// ProcessFeeds processes all feeds, feeds that could not be processed are left out of the result
+// the returned error joins a *ProcessError for every feed that could not be processed
+func ProcessFeeds(fs Feeds, options Options) ([]Feed, error) {
+	resolver := options.resolver(fs)
+	fs, errs := fs.generated(context.Background(), resolver, options.SkipData)
+
+	// resolve the data of all feeds at once, so the lookups are spread over all workers
+	resolved := resolved{}
+	if !options.SkipData {
+		resolved = options.resolve(resolver, fs.Feeds)
+		if options.UpdatedFromData {
+			fs = fs.updatedFromData(resolved.resources)
+		}
+	}
+
 	processedFeeds := make([]Feed, 0, len(fs.Feeds))
 
 	for _, f := range fs.Feeds {
-		d := GetDefaultFeedProperties()
-		_ = mergo.Merge(&f, d)
-
-		links := f.Link
-		if f.Self != nil {
-			links = append(links, Self(*f.Self))
+		processed, err := processFeed(f, fs, options, resolved)
+		if err != nil {
+			errs = append(errs, err)
+			continue
 		}
-		if f.Describedby != nil {
-			links = append(links, DescribedBy(*f.Describedby))
+		processedFeeds = append(processedFeeds, processed)
+	}
+	return processedFeeds, errors.Join(errs...)
+}
+
+// ProcessFeed processes a single feed, the other feeds are used to derive the 'updated' values of its entries,
+// the generators of the feeds need to be expanded first with Feeds.Generated, so they are listed once for all feeds
+func ProcessFeed(f Feed, fs Feeds, options Options) (Feed, error) {
+	if len(f.Generate) > 0 || slices.ContainsFunc(fs.Feeds, func(other Feed) bool { return len(other.Generate) > 0 }) {
+		return f, &ProcessError{FeedID: f.ID, Err: errors.New("the generators need to be expanded first, see Feeds.Generated")}
+	}
+
+	resolved := resolved{}
+	if !options.SkipData {
+		resolved = options.resolve(options.resolver(fs), []Feed{f})
+		if options.UpdatedFromData {
+			f = Feeds{Feeds: []Feed{f}}.updatedFromData(resolved.resources).Feeds[0]
 		}
-		if f.Search != nil {
-			links = append(links, Search(*f.Search))
+	}
+	return processFeed(f, fs, options, resolved)
+}
+
+// resolved contains the results of Resolver.ResolveAll, Resolver.ChecksumAll and Resolver.extentAll
+type resolved struct {
+	resources map[string]Resource
+	checksums map[string]string
+	extents   map[string]extent
+	// unsupported are the errors of the extents of data in an unsupported CRS
+	unsupported map[string]error
+	errs        map[string]error
+}
+
+func processFeed(f Feed, fs Feeds, options Options, resolved resolved) (Feed, error) {
+	// work on copies, so the given Feeds can be processed again
+	f.Link = slices.Clone(f.Link)
+	f.Entry = slices.Clone(f.Entry)
+	for entryIndex, entry := range f.Entry {
+		f.Entry[entryIndex].Link = slices.Clone(entry.Link)
+		f.Entry[entryIndex].Category = entry.withCRS()
+	}
+
+	if err := f.withDefaults(fs.Defaults); err != nil {
+		return f, &ProcessError{FeedID: f.ID, Err: err}
+	}
+
+	links := f.Link
+	if f.Self != nil {
+		links = append(links, Self(*f.Self))
+	}
+	if f.Describedby != nil {
+		links = append(links, DescribedBy(*f.Describedby))
+	}
+	if f.Search != nil {
+		links = append(links, Search(*f.Search))
+	}
+	if f.Up != nil {
+		links = append(links, Up(*f.Up))
+	}
+
+	f.Link = links
+
+	for i, l := range f.Link {
+		f.Link[i] = l.SetHrefLang(*f.Lang)
+	}
+
+	f.recentUpdated(fs)
+	f.normalizeTimes()
+
+	if options.Checksums != nil {
+		if ns, ok := options.Checksums.namespace(); ok {
+			f.Attrs = append(slices.Clone(f.Attrs), ns)
 		}
-		if f.Up != nil {
-			links = append(links, Up(*f.Up))
+	}
+
+	for entryIndex := range f.Entry {
+		if err := f.processEntry(entryIndex, options, resolved); err != nil {
+			return f, err
 		}
+	}
+	if f.Gml == `` && slices.ContainsFunc(f.Entry, func(e Entry) bool { return e.Where != nil }) {
+		f.Gml = gmlNamespace
+	}
+
+	// reset predefined
+	f.Self = nil
+	f.Describedby = nil
+	f.Search = nil
+	f.Up = nil
 
-		f.Link = links
+	return f, nil
+}
 
-		for i, l := range f.Link {
-			f.Link[i] = l.SetHrefLang(*f.Lang)
+// processEntry fills the links of an entry with the resolved `data`, checksums and extents,
+// the polygon of the entry is the extent of all its links
+func (f *Feed) processEntry(index int, options Options, resolved resolved) error {
+	entry := &f.Entry[index]
+	var entryExtent *extent
+
+	for linkIndex, link := range entry.Link {
+		if link.Data != nil && !options.SkipData {
+			if err, ok := resolved.errs[*link.Data]; ok {
+				return &ProcessError{FeedID: f.ID, EntryID: entry.ID, Href: link.Href, Err: err}
+			}
+			if sum, ok := resolved.checksums[*link.Data]; ok && link.SHA256 == nil {
+				link.SHA256 = &sum
+			}
+			if err, ok := resolved.unsupported[*link.Data]; ok && link.Bbox == nil {
+				link.noExtent = err
+			}
+			if e, ok := resolved.extents[*link.Data]; ok {
+				if link.Bbox == nil {
+					box := e.box()
+					link.Bbox = &box
+				}
+				if entryExtent != nil {
+					e = entryExtent.union(e)
+				}
+				entryExtent = &e
+			}
+			link = link.apply(resolved.resources[*link.Data])
+		}
+		// the `data` is never published, also not when it is skipped
+		link.skipped = link.Data != nil && options.SkipData
+		link.Data = nil
+		if options.Checksums != nil {
+			link.Attrs = slices.Clone(link.Attrs)
+			link = link.applyChecksum(*options.Checksums)
 		}
+		entry.Link[linkIndex] = link.SetHrefLang(*f.Lang)
+	}
 
-		f.recentUpdated(fs)
+	if entryExtent != nil && entry.Polygon == `` {
+		entry.Polygon = entryExtent.polygon()
+	}
+	return nil
+}
 
+// dataURIs returns the `data` of all links of all entries
+func (fs Feeds) dataURIs() []string {
+	var uris []string
+	for _, f := range fs.Feeds {
 		for _, entry := range f.Entry {
-			for linkIndex, link := range entry.Link {
+			for _, link := range entry.Link {
 				if link.Data != nil {
-					res, err := http.Head(*link.Data)
-					if err != nil {
-						panic(err)
-					}
-					defer res.Body.Close()
-
-					if len(link.Length) == 0 {
-						link.Length = res.Header.Get("Content-Length")
-					}
-					if len(link.Type) == 0 {
-						link.Type = res.Header.Get("Content-Type")
-					}
-
-					link.Data = nil
-					entry.Link[linkIndex] = link
+					uris = append(uris, *link.Data)
 				}
-				entry.Link[linkIndex] = link.SetHrefLang(*f.Lang)
 			}
 		}
+	}
+	return uris
+}
+
+// checksumURIs returns the `data` of all links of all entries without a checksum
+func (fs Feeds) checksumURIs() []string {
+	var uris []string
+	for _, f := range fs.Feeds {
+		for _, entry := range f.Entry {
+			for _, link := range entry.Link {
+				if link.Data != nil && link.SHA256 == nil {
+					uris = append(uris, *link.Data)
+				}
+			}
+		}
+	}
+	return uris
+}
 
-		// reset predefined
-		f.Self = nil
-		f.Describedby = nil
-		f.Search = nil
-		f.Up = nil
+// updatedFromData returns a copy of the Feeds in which the entries without an 'updated'
+// get the newest Last-Modified of their resolved `data` links
+func (fs Feeds) updatedFromData(resources map[string]Resource) Feeds {
+	feeds := make([]Feed, 0, len(fs.Feeds))
+	for _, f := range fs.Feeds {
+		f.Entry = slices.Clone(f.Entry)
+		for entryIndex, entry := range f.Entry {
+			if entry.Updated != nil {
+				continue
+			}
+			var newest time.Time
+			for _, link := range entry.Link {
+				if link.Data == nil {
+					continue
+				}
+				if lastModified := resources[*link.Data].LastModified; lastModified.After(newest) {
+					newest = lastModified
+				}
+			}
+			if !newest.IsZero() {
+				updated := formatTime(newest)
+				f.Entry[entryIndex].Updated = &updated
+			}
+		}
+		feeds = append(feeds, f)
+	}
+	fs.Feeds = feeds
+	return fs
+}
 
-		processedFeeds = append(processedFeeds, f)
+// apply fills the length and type of the link with the resolved Resource, values from the configuration take precedence
+func (l *Link) apply(resource Resource) Link {
+	if len(l.Length) == 0 {
+		l.Length = resource.Length
 	}
-	return processedFeeds
+	if len(l.Type) == 0 {
+		l.Type = resource.Type
+	}
+
+	l.Data = nil
+	return *l
 }
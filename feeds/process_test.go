@@ -1,6 +1,7 @@
 package feeds
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -175,9 +176,98 @@ func TestProcessFeed(t *testing.T) {
 	}
 
 	for k, test := range tests {
-		output := ProcessFeeds(test.input)
+		output, err := ProcessFeeds(test.input, Options{})
+		if err != nil {
+			t.Errorf("test: %d, unexpected error: %v", k, err)
+		}
 		if !reflect.DeepEqual(output, test.expected) {
 			t.Errorf("test: %d, expected: \n%#v+ \ngot: \n%#v+", k, test.expected, output)
 		}
 	}
 }
+
+func TestProcessFeedsError(t *testing.T) {
+	input := Feeds{Feeds: []Feed{
+		{
+			ID: "http://xyz.org/download/en.xml",
+		},
+		{
+			ID: "http://xyz.org/data/abc/waternetwork.xml",
+			Entry: []Entry{{
+				ID: "http://xyz.org/data/abc/waternetwork_25832.gml",
+				Link: []Link{{
+					Href: "http://xyz.org/data/abc/waternetwork_25832.gml",
+					Data: sp("unknown://backend/waternetwork_25832.gml"),
+				}},
+			}},
+		},
+	}}
+
+	output, err := ProcessFeeds(input, Options{})
+	if len(output) != 1 || output[0].ID != "http://xyz.org/download/en.xml" {
+		t.Errorf("expected only the service feed to be processed, got: %v", output)
+	}
+
+	var processErr *ProcessError
+	if !errors.As(err, &processErr) {
+		t.Fatalf("expected a ProcessError, got: %v", err)
+	}
+	expected := ProcessError{
+		FeedID:  "http://xyz.org/data/abc/waternetwork.xml",
+		EntryID: "http://xyz.org/data/abc/waternetwork_25832.gml",
+		Href:    "http://xyz.org/data/abc/waternetwork_25832.gml",
+	}
+	if processErr.FeedID != expected.FeedID || processErr.EntryID != expected.EntryID || processErr.Href != expected.Href {
+		t.Errorf("expected: %+v \ngot: %+v", expected, processErr)
+	}
+
+	// with SkipData the data is not resolved, so both feeds are processed
+	output, err = ProcessFeeds(input, Options{SkipData: true})
+	if err != nil || len(output) != 2 {
+		t.Errorf("expected both feeds to be processed, got: %v, %v", output, err)
+	}
+}
+
+func TestProcessFeedGenerators(t *testing.T) {
+	f := Feed{ID: "http://xyz.org/data/abc/waternetwork.xml", Generate: []EntryGenerator{{Source: "s3://abc/data"}}}
+	_, err := ProcessFeed(f, Feeds{Feeds: []Feed{f}}, Options{SkipData: true})
+	var processError *ProcessError
+	if !errors.As(err, &processError) || processError.FeedID != f.ID {
+		t.Errorf("expected a ProcessError for the generators, got: %v", err)
+	}
+}
+
+func TestProcessFeedsSkipDataValid(t *testing.T) {
+	var updated = "2021-03-31T13:45:03Z"
+	input := Feeds{Feeds: []Feed{{
+		ID:       "http://xyz.org/data/abc/waternetwork.xml",
+		Self:     &Link{Href: "http://xyz.org/data/abc/waternetwork.xml"},
+		Title:    "XYZ Example INSPIRE Dataset ABC Download",
+		Subtitle: "INSPIRE Download Service of organisation XYZ",
+		Rights:   "Copyright (c) 2012, XYZ; all rights reserved",
+		Updated:  &updated,
+		Author:   Author{Name: "John Doe", Email: "doe@xyz.org"},
+		Entry: []Entry{{
+			ID:      "http://xyz.org/data/abc/waternetwork_25832.gml",
+			Updated: &updated,
+			CRS:     []int{25832},
+			Link: []Link{{
+				Href: "http://xyz.org/data/abc/waternetwork_25832.gml",
+				Rel:  "alternate",
+				Data: sp("unknown://backend/waternetwork_25832.gml"),
+			}},
+		}},
+	}}}
+
+	output, err := ProcessFeeds(input, Options{SkipData: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link := output[0].Entry[0].Link[0]; link.Data != nil || link.Type != "" {
+		t.Errorf("expected the data to be skipped, got: %+v", link)
+	}
+	// the type of a skipped data link is not known, so it is not validated
+	if report := (Feeds{Feeds: output}).Valid(); report.HasErrors() {
+		t.Errorf("expected no errors, got: %v", report.Errors())
+	}
+}
@@ -0,0 +1,321 @@
+package feeds
+
+import (
+	"math"
+)
+
+// ellipsoid is defined by its semi-major axis and flattening
+type ellipsoid struct {
+	a float64
+	f float64
+}
+
+var (
+	grs80         = ellipsoid{a: 6378137, f: 1 / 298.257222101}
+	wgs84         = ellipsoid{a: 6378137, f: 1 / 298.257223563}
+	bessel1841    = ellipsoid{a: 6377397.155, f: 1 / 299.1528128}
+	airy1830      = ellipsoid{a: 6377563.396, f: 1 / 299.3249646}
+	international = ellipsoid{a: 6378388, f: 1 / 297}
+)
+
+func (el ellipsoid) e2() float64 {
+	return el.f * (2 - el.f)
+}
+
+func (el ellipsoid) e() float64 {
+	return math.Sqrt(el.e2())
+}
+
+// helmert is a 7 parameter transformation to WGS 84 in the position vector convention,
+// the translations are in metres, the rotations in arc seconds and the scale in parts per million
+type helmert struct {
+	tx, ty, tz float64
+	rx, ry, rz float64
+	s          float64
+}
+
+var (
+	amersfoortToWGS84 = &helmert{tx: 565.417, ty: 50.3319, tz: 465.552, rx: -0.398957, ry: 0.343988, rz: -1.8774, s: 4.0725}
+	dhdnToWGS84       = &helmert{tx: 598.1, ty: 73.7, tz: 418.2, rx: 0.202, ry: 0.045, rz: -2.455, s: 6.7}
+	osgb36ToWGS84     = &helmert{tx: 446.448, ty: -125.157, tz: 542.06, rx: 0.15, ry: 0.247, rz: 0.842, s: -20.489}
+	belge1972ToWGS84  = &helmert{tx: -106.8686, ty: 52.2978, tz: -103.7239, rx: 0.3366, ry: -0.457, rz: 1.8422, s: -1.2747}
+)
+
+// apply transforms a latitude and longitude in radians on the given ellipsoid to WGS 84
+func (h *helmert) apply(el ellipsoid, lat, lon float64) (float64, float64) {
+	// geodetic to geocentric, at the height of the ellipsoid
+	e2 := el.e2()
+	n := el.a / math.Sqrt(1-e2*math.Pow(math.Sin(lat), 2))
+	x := n * math.Cos(lat) * math.Cos(lon)
+	y := n * math.Cos(lat) * math.Sin(lon)
+	z := n * (1 - e2) * math.Sin(lat)
+
+	arcsec := math.Pi / (180 * 3600)
+	rx, ry, rz := h.rx*arcsec, h.ry*arcsec, h.rz*arcsec
+	m := 1 + h.s*1e-6
+	x, y, z = h.tx+m*(x-rz*y+ry*z), h.ty+m*(rz*x+y-rx*z), h.tz+m*(-ry*x+rx*y+z)
+
+	// geocentric to geodetic on WGS 84
+	e2 = wgs84.e2()
+	p := math.Hypot(x, y)
+	lat = math.Atan2(z, p*(1-e2))
+	for range 10 {
+		n = wgs84.a / math.Sqrt(1-e2*math.Pow(math.Sin(lat), 2))
+		lat = math.Atan2(z+e2*n*math.Sin(lat), p)
+	}
+	return lat, math.Atan2(y, x)
+}
+
+// projection is the inverse of a map projection, from easting and northing to latitude and longitude in radians
+type projection func(e, n float64) (lat, lon float64)
+
+// crsDefinition describes how the coordinates of a CRS are transformed to WGS 84 latitude and longitude
+type crsDefinition struct {
+	ellipsoid ellipsoid
+	// inverse is nil for a geographic CRS
+	inverse projection
+	// northingFirst is true when the first axis is the latitude or northing
+	northingFirst bool
+	// toWGS84 is nil for a datum that is considered equal to WGS 84, like ETRS89
+	toWGS84 *helmert
+}
+
+// toLatLon transforms a position in the axis order of the CRS to WGS 84 latitude and longitude in degrees
+func (d crsDefinition) toLatLon(first, second float64) (float64, float64) {
+	e, n := first, second
+	if d.northingFirst {
+		e, n = second, first
+	}
+
+	var lat, lon float64
+	if d.inverse == nil {
+		lat, lon = radians(n), radians(e)
+	} else {
+		lat, lon = d.inverse(e, n)
+	}
+	if d.toWGS84 != nil {
+		lat, lon = d.toWGS84.apply(d.ellipsoid, lat, lon)
+	}
+	return degrees(lat), degrees(lon)
+}
+
+// crsDefinitions returns the definitions of the EPSG codes for which extents can be transformed
+//
+//nolint:funlen
+func crsDefinitions() map[int]crsDefinition {
+	defs := map[int]crsDefinition{
+		4258: {ellipsoid: grs80, northingFirst: true},
+		4326: {ellipsoid: wgs84, northingFirst: true},
+		4937: {ellipsoid: grs80, northingFirst: true},
+		4979: {ellipsoid: wgs84, northingFirst: true},
+		4289: {ellipsoid: bessel1841, northingFirst: true, toWGS84: amersfoortToWGS84},
+		3034: {ellipsoid: grs80, northingFirst: true, inverse: lambertConformal(grs80, 35, 65, 52, 10, 4000000, 2800000)},
+		3035: {ellipsoid: grs80, northingFirst: true, inverse: lambertAzimuthal(grs80, 52, 10, 4321000, 3210000)},
+		3857: {ellipsoid: wgs84, inverse: pseudoMercator},
+		3395: {ellipsoid: wgs84, inverse: mercator(wgs84)},
+		2154: {ellipsoid: grs80, inverse: lambertConformal(grs80, 49, 44, 46.5, 3, 700000, 6600000)},
+		3812: {ellipsoid: grs80, inverse: lambertConformal(grs80, 49.8333333333333, 51.1666666666667, 50.797815, 4.35921583333333, 649328, 665262)},
+		31370: {ellipsoid: international, toWGS84: belge1972ToWGS84,
+			inverse: lambertConformal(international, 51.16666723333333, 49.8333339, 90, 4.367486666666666, 150000.013, 5400088.438)},
+		28992: {ellipsoid: bessel1841, toWGS84: amersfoortToWGS84,
+			inverse: obliqueStereographic(bessel1841, 52.15616055555555, 5.38763888888889, 0.9999079, 155000, 463000)},
+		27700: {ellipsoid: airy1830, toWGS84: osgb36ToWGS84,
+			inverse: transverseMercator(airy1830, 49, -2, 0.9996012717, 400000, -100000)},
+	}
+	// the compound CRSs with a height have the same horizontal axes
+	defs[7415] = defs[28992]
+	defs[7409] = defs[4258]
+	defs[7423] = defs[4258]
+
+	for zone := 28; zone <= 38; zone++ {
+		defs[25800+zone] = crsDefinition{ellipsoid: grs80, inverse: utm(grs80, zone, false)}
+	}
+	// ETRS89 / TMzn has the UTM parameters with the northing first
+	for zone := 26; zone <= 39; zone++ {
+		defs[3012+zone] = crsDefinition{ellipsoid: grs80, northingFirst: true, inverse: utm(grs80, zone, false)}
+	}
+	for zone := 1; zone <= 60; zone++ {
+		defs[32600+zone] = crsDefinition{ellipsoid: wgs84, inverse: utm(wgs84, zone, false)}
+		defs[32700+zone] = crsDefinition{ellipsoid: wgs84, inverse: utm(wgs84, zone, true)}
+	}
+	// DHDN / 3-degree Gauss-Kruger has the northing first
+	for zone := 2; zone <= 5; zone++ {
+		defs[31464+zone] = crsDefinition{ellipsoid: bessel1841, northingFirst: true, toWGS84: dhdnToWGS84,
+			inverse: transverseMercator(bessel1841, 0, float64(3*zone), 1, float64(zone)*1000000+500000, 0)}
+	}
+	return defs
+}
+
+var crsDefs = crsDefinitions()
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+func utm(el ellipsoid, zone int, south bool) projection {
+	falseNorthing := 0.0
+	if south {
+		falseNorthing = 10000000
+	}
+	return transverseMercator(el, 0, float64(6*zone-183), 0.9996, 500000, falseNorthing)
+}
+
+// meridianArc returns the distance along the meridian from the equator to the latitude
+func meridianArc(el ellipsoid, lat float64) float64 {
+	e2 := el.e2()
+	e4, e6 := e2*e2, e2*e2*e2
+	return el.a * ((1-e2/4-3*e4/64-5*e6/256)*lat -
+		(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*lat) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*lat) -
+		(35*e6/3072)*math.Sin(6*lat))
+}
+
+// transverseMercator returns the inverse of the Transverse Mercator projection (EPSG method 9807)
+func transverseMercator(el ellipsoid, lat0, lon0, k0, falseEasting, falseNorthing float64) projection {
+	e2 := el.e2()
+	ep2 := e2 / (1 - e2)
+	m0 := meridianArc(el, radians(lat0))
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+
+	return func(e, n float64) (float64, float64) {
+		m := m0 + (n-falseNorthing)/k0
+		mu := m / (el.a * (1 - e2/4 - 3*e2*e2/64 - 5*e2*e2*e2/256))
+		phi1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+			(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+			(151*math.Pow(e1, 3)/96)*math.Sin(6*mu) +
+			(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+
+		sin, cos, tan := math.Sin(phi1), math.Cos(phi1), math.Tan(phi1)
+		c1 := ep2 * cos * cos
+		t1 := tan * tan
+		n1 := el.a / math.Sqrt(1-e2*sin*sin)
+		r1 := el.a * (1 - e2) / math.Pow(1-e2*sin*sin, 1.5)
+		d := (e - falseEasting) / (n1 * k0)
+
+		lat := phi1 - (n1*tan/r1)*(d*d/2-
+			(5+3*t1+10*c1-4*c1*c1-9*ep2)*math.Pow(d, 4)/24+
+			(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*math.Pow(d, 6)/720)
+		lon := radians(lon0) + (d-
+			(1+2*t1+c1)*math.Pow(d, 3)/6+
+			(5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*math.Pow(d, 5)/120)/cos
+		return lat, lon
+	}
+}
+
+// lambertAzimuthal returns the inverse of the Lambert Azimuthal Equal Area projection (EPSG method 9820)
+func lambertAzimuthal(el ellipsoid, lat0, lon0, falseEasting, falseNorthing float64) projection {
+	e2, ecc := el.e2(), el.e()
+	q := func(phi float64) float64 {
+		sin := math.Sin(phi)
+		return (1 - e2) * (sin/(1-e2*sin*sin) - (1/(2*ecc))*math.Log((1-ecc*sin)/(1+ecc*sin)))
+	}
+	phi0 := radians(lat0)
+	qp := q(math.Pi / 2)
+	beta0 := math.Asin(q(phi0) / qp)
+	rq := el.a * math.Sqrt(qp/2)
+	d := el.a * (math.Cos(phi0) / math.Sqrt(1-e2*math.Pow(math.Sin(phi0), 2))) / (rq * math.Cos(beta0))
+
+	return func(e, n float64) (float64, float64) {
+		x, y := e-falseEasting, n-falseNorthing
+		rho := math.Hypot(x/d, d*y)
+		if rho == 0 {
+			return phi0, radians(lon0)
+		}
+		c := 2 * math.Asin(rho/(2*rq))
+		beta := math.Asin(math.Cos(c)*math.Sin(beta0) + (d*y*math.Sin(c)*math.Cos(beta0))/rho)
+		lon := radians(lon0) + math.Atan2(x*math.Sin(c), d*rho*math.Cos(beta0)*math.Cos(c)-d*d*y*math.Sin(beta0)*math.Sin(c))
+		lat := beta + (e2/3+31*e2*e2/180+517*math.Pow(e2, 3)/5040)*math.Sin(2*beta) +
+			(23*e2*e2/360+251*math.Pow(e2, 3)/3780)*math.Sin(4*beta) +
+			(761*math.Pow(e2, 3)/45360)*math.Sin(6*beta)
+		return lat, lon
+	}
+}
+
+// conformalLatitude returns the latitude for the isometric value t, which is used by the conformal projections
+func conformalLatitude(ecc, t float64) float64 {
+	phi := math.Pi/2 - 2*math.Atan(t)
+	for range 15 {
+		sin := ecc * math.Sin(phi)
+		phi = math.Pi/2 - 2*math.Atan(t*math.Pow((1-sin)/(1+sin), ecc/2))
+	}
+	return phi
+}
+
+// lambertConformal returns the inverse of the Lambert Conic Conformal (2SP) projection (EPSG method 9802)
+func lambertConformal(el ellipsoid, lat1, lat2, lat0, lon0, falseEasting, falseNorthing float64) projection {
+	e2, ecc := el.e2(), el.e()
+	m := func(phi float64) float64 {
+		return math.Cos(phi) / math.Sqrt(1-e2*math.Pow(math.Sin(phi), 2))
+	}
+	t := func(phi float64) float64 {
+		sin := ecc * math.Sin(phi)
+		return math.Tan(math.Pi/4-phi/2) / math.Pow((1-sin)/(1+sin), ecc/2)
+	}
+	phi1, phi2 := radians(lat1), radians(lat2)
+	n := (math.Log(m(phi1)) - math.Log(m(phi2))) / (math.Log(t(phi1)) - math.Log(t(phi2)))
+	f := m(phi1) / (n * math.Pow(t(phi1), n))
+	rF := el.a * f * math.Pow(t(radians(lat0)), n)
+
+	return func(e, north float64) (float64, float64) {
+		x, y := e-falseEasting, rF-(north-falseNorthing)
+		sign := math.Copysign(1, n)
+		r := sign * math.Hypot(x, y)
+		theta := math.Atan2(sign*x, sign*y)
+		return conformalLatitude(ecc, math.Pow(r/(el.a*f), 1/n)), theta/n + radians(lon0)
+	}
+}
+
+// obliqueStereographic returns the inverse of the Oblique Stereographic projection (EPSG method 9809)
+func obliqueStereographic(el ellipsoid, lat0, lon0, k0, falseEasting, falseNorthing float64) projection {
+	e2, ecc := el.e2(), el.e()
+	phi0 := radians(lat0)
+	sin0 := math.Sin(phi0)
+	rho0 := el.a * (1 - e2) / math.Pow(1-e2*sin0*sin0, 1.5)
+	nu0 := el.a / math.Sqrt(1-e2*sin0*sin0)
+	r := math.Sqrt(rho0 * nu0)
+	n := math.Sqrt(1 + e2*math.Pow(math.Cos(phi0), 4)/(1-e2))
+	s1 := (1 + sin0) / (1 - sin0)
+	s2 := (1 - ecc*sin0) / (1 + ecc*sin0)
+	w1 := math.Pow(s1*math.Pow(s2, ecc), n)
+	sinChi0 := (w1 - 1) / (w1 + 1)
+	c := (n + sin0) * (1 - sinChi0) / ((n - sin0) * (1 + sinChi0))
+	w2 := c * w1
+	chi0 := math.Asin((w2 - 1) / (w2 + 1))
+	lambda0 := radians(lon0)
+
+	return func(e, north float64) (float64, float64) {
+		x, y := e-falseEasting, north-falseNorthing
+		g := 2 * r * k0 * math.Tan(math.Pi/4-chi0/2)
+		h := 4*r*k0*math.Tan(chi0) + g
+		i := math.Atan(x / (h + y))
+		j := math.Atan(x/(g-y)) - i
+		chi := chi0 + 2*math.Atan((y-x*math.Tan(j/2))/(2*r*k0))
+		lambda := j + 2*i + lambda0
+		lon := (lambda-lambda0)/n + lambda0
+
+		psi := 0.5 * math.Log((1+math.Sin(chi))/(c*(1-math.Sin(chi)))) / n
+		phi := 2*math.Atan(math.Exp(psi)) - math.Pi/2
+		for range 15 {
+			sin := ecc * math.Sin(phi)
+			psiI := math.Log(math.Tan(phi/2+math.Pi/4) * math.Pow((1-sin)/(1+sin), ecc/2))
+			phi -= (psiI - psi) * math.Cos(phi) * (1 - e2*math.Pow(math.Sin(phi), 2)) / (1 - e2)
+		}
+		return phi, lon
+	}
+}
+
+// mercator returns the inverse of the Mercator (variant A) projection with a scale of 1 at the equator
+func mercator(el ellipsoid) projection {
+	return func(e, n float64) (float64, float64) {
+		return conformalLatitude(el.e(), math.Exp(-n/el.a)), e / el.a
+	}
+}
+
+// pseudoMercator is the inverse of the Popular Visualisation Pseudo Mercator projection, which uses a sphere
+func pseudoMercator(e, n float64) (float64, float64) {
+	return math.Atan(math.Sinh(n / wgs84.a)), e / wgs84.a
+}
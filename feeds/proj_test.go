@@ -0,0 +1,39 @@
+package feeds
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCRSDefinitionToLatLon(t *testing.T) {
+	var tests = []struct {
+		code          int
+		first, second float64
+		lat, lon      float64
+		tolerance     float64
+	}{
+		// the examples of IOGP Guidance Note 7-2
+		0: {code: 3035, first: 2999718.85, second: 3962799.45, lat: 50, lon: 5, tolerance: 1e-7},
+		1: {code: 2154, first: 700000, second: 6600000, lat: 46.5, lon: 3, tolerance: 1e-9},
+		2: {code: 3857, first: 0, second: 0, lat: 0, lon: 0, tolerance: 1e-9},
+		3: {code: 3857, first: 20037508.342789244, second: 0, lat: 0, lon: 180, tolerance: 1e-9},
+		4: {code: 25832, first: 500000, second: 0, lat: 0, lon: 9, tolerance: 1e-9},
+		5: {code: 3044, first: 0, second: 500000, lat: 0, lon: 9, tolerance: 1e-9},
+		// the Amersfoort tower, the origin of RD, transformed to WGS 84
+		6: {code: 28992, first: 155000, second: 463000, lat: 52.155172, lon: 5.387204, tolerance: 1e-5},
+		7: {code: 4258, first: 52, second: 5, lat: 52, lon: 5, tolerance: 1e-12},
+		// the horizontal axes of a compound CRS with a height
+		8: {code: 7409, first: 52, second: 5, lat: 52, lon: 5, tolerance: 1e-12},
+	}
+
+	for k, test := range tests {
+		def, err := epsgDefinition(test.code)
+		if err != nil {
+			t.Fatalf("test: %d, unexpected error: %v", k, err)
+		}
+		lat, lon := def.toLatLon(test.first, test.second)
+		if math.Abs(lat-test.lat) > test.tolerance || math.Abs(lon-test.lon) > test.tolerance {
+			t.Errorf("test: %d, expected: %f %f \ngot: %f %f", k, test.lat, test.lon, lat, lon)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package feeds
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity of a validation Finding
+type Severity string
+
+const (
+	// SeverityError marks a violated TG Requirement, the feed is not valid
+	SeverityError Severity = `error`
+	// SeverityWarning marks a violated TG Recommendation, the feed is still valid
+	SeverityWarning Severity = `warning`
+)
+
+// Finding describes a single violated TG Requirement or Recommendation
+type Finding struct {
+	Severity    Severity
+	Requirement string // e.g. "TG Requirement 5"
+	Path        string // e.g. "feeds[1].entry[2].link[0].bbox"
+	Message     string
+}
+
+// String returns a human readable representation of the Finding
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Path, f.Message)
+}
+
+// Report contains all the findings of a validation
+type Report struct {
+	Findings []Finding
+}
+
+// Error adds a finding with SeverityError to the Report
+func (r *Report) Error(requirement, path, message string) {
+	r.Findings = append(r.Findings, Finding{Severity: SeverityError, Requirement: requirement, Path: path, Message: message})
+}
+
+// Warning adds a finding with SeverityWarning to the Report
+func (r *Report) Warning(requirement, path, message string) {
+	r.Findings = append(r.Findings, Finding{Severity: SeverityWarning, Requirement: requirement, Path: path, Message: message})
+}
+
+// Merge adds the findings of another Report, prefixing their paths
+func (r *Report) Merge(prefix string, other Report) {
+	for _, f := range other.Findings {
+		f.Path = joinPath(prefix, f.Path)
+		r.Findings = append(r.Findings, f)
+	}
+}
+
+// HasErrors returns true when the Report contains at least one finding with SeverityError
+func (r *Report) HasErrors() bool {
+	return len(r.Errors()) > 0
+}
+
+// Errors returns the findings with SeverityError
+func (r *Report) Errors() []Finding {
+	return r.filter(SeverityError)
+}
+
+// Warnings returns the findings with SeverityWarning
+func (r *Report) Warnings() []Finding {
+	return r.filter(SeverityWarning)
+}
+
+// String returns all the findings, one per line
+func (r *Report) String() string {
+	lines := make([]string, 0, len(r.Findings))
+	for _, f := range r.Findings {
+		lines = append(lines, f.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *Report) filter(severity Severity) []Finding {
+	var findings []Finding
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+func joinPath(prefix, path string) string {
+	switch {
+	case prefix == ``:
+		return path
+	case path == ``:
+		return prefix
+	default:
+		return prefix + `.` + path
+	}
+}
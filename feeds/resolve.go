@@ -0,0 +1,212 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Resource contains the properties of a resolved `data` source
+type Resource struct {
+	Length       string    `json:"length,omitempty"`
+	Type         string    `json:"type,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified time.Time `json:"lastModified,omitzero"`
+}
+
+// ResolverOptions configures a Resolver
+type ResolverOptions struct {
+	// Workers is the number of `data` sources that are resolved concurrently
+	Workers int
+	// Timeout is the maximum duration of a single request
+	Timeout time.Duration
+	// Retries is the number of times a failed request is retried
+	Retries int
+	// Backoff is the delay before the first retry, it doubles for every next retry
+	Backoff time.Duration
+	// MaxConnsPerHost limits the number of connections to a single host
+	MaxConnsPerHost int
+	// S3 configures the access to S3 compatible object storage for `s3://bucket/key` data
+	S3 S3
+	// Cache keeps the resolved Resources and their checksums between runs, nil disables the cache
+	Cache *Cache
+	// MaxAge is the duration for which a cached Resource is used without a request,
+	// an older Resource is revalidated with a conditional request
+	MaxAge time.Duration
+	// Offline resolves everything from the Cache, without any requests
+	Offline bool
+}
+
+// DefaultResolverOptions returns the ResolverOptions used when none are given
+func DefaultResolverOptions() ResolverOptions {
+	return ResolverOptions{
+		Workers:         8,
+		Timeout:         30 * time.Second,
+		Retries:         3,
+		Backoff:         500 * time.Millisecond,
+		MaxConnsPerHost: 4,
+	}
+}
+
+// Resolver resolves the `data` sources of links, these can be HTTP(S) URLs, S3 objects or local files
+type Resolver struct {
+	options ResolverOptions
+	client  *http.Client
+}
+
+// NewResolver creates a Resolver with its own HTTP client
+func NewResolver(options ResolverOptions) *Resolver {
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxConnsPerHost = options.MaxConnsPerHost
+	transport.MaxIdleConnsPerHost = options.MaxConnsPerHost
+
+	options.S3 = options.S3.withEnvironment()
+
+	return &Resolver{
+		options: options,
+		client:  &http.Client{Transport: transport},
+	}
+}
+
+// Resolve resolves a single `data` source, failed requests are retried with a backoff
+// a cached Resource is used when it is younger than the MaxAge or when the Resolver is Offline
+func (r *Resolver) Resolve(ctx context.Context, uri string) (Resource, error) {
+	cached, resolved, ok := r.options.Cache.resource(uri)
+	if ok && (r.options.Offline || time.Since(resolved) < r.options.MaxAge) {
+		return cached, nil
+	}
+	if r.options.Offline {
+		return Resource{}, fmt.Errorf("%s was never resolved, it is not in the cache", uri)
+	}
+
+	resource, err := retry(ctx, r.options, func() (Resource, error) {
+		return r.resolve(ctx, uri, cached)
+	})
+	if err != nil {
+		return resource, err
+	}
+	r.options.Cache.setResource(uri, resource, time.Now())
+	return resource, nil
+}
+
+// ResolveAll resolves the unique `data` sources concurrently with a bounded number of workers
+// it returns the resolved resources and the errors of the sources that could not be resolved
+func (r *Resolver) ResolveAll(ctx context.Context, uris []string) (map[string]Resource, map[string]error) {
+	return forAll(r.options.Workers, uris, func(uri string) (Resource, error) {
+		return r.Resolve(ctx, uri)
+	})
+}
+
+// retry calls fn until it succeeds, it fails with an error that is not retryable or the retries are exhausted
+func retry[T any](ctx context.Context, options ResolverOptions, fn func() (T, error)) (T, error) {
+	backoff := options.Backoff
+	for attempt := 0; ; attempt++ {
+		result, err := fn()
+		var retry *retryableError
+		if err == nil || !errors.As(err, &retry) || attempt >= options.Retries {
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// forAll calls fn for the unique uris concurrently with the given number of workers
+// it returns the results and the errors by uri
+func forAll[T any](workers int, uris []string, fn func(uri string) (T, error)) (map[string]T, map[string]error) {
+	results := make(map[string]T)
+	errs := make(map[string]error)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan string)
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for uri := range queue {
+				result, err := fn(uri)
+
+				mu.Lock()
+				if err != nil {
+					errs[uri] = err
+				} else {
+					results[uri] = result
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool)
+	for _, uri := range uris {
+		if !seen[uri] {
+			seen[uri] = true
+			queue <- uri
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	return results, errs
+}
+
+// Open opens a `data` source for reading, the caller closes it
+func (r *Resolver) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	u, src, err := r.source(uri)
+	if err != nil {
+		return nil, err
+	}
+	return src.get(ctx, u)
+}
+
+// source returns the source that resolves the scheme of the uri
+func (r *Resolver) source(uri string) (*url.URL, source, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch u.Scheme {
+	case `http`, `https`:
+		return u, httpSource{client: r.client}, nil
+	case `s3`:
+		return u, s3Source{client: r.client, config: r.options.S3}, nil
+	case `file`:
+		return u, fileSource{}, nil
+	case ``:
+		// a plain path, which can contain characters that have a special meaning in a URI
+		return &url.URL{Path: uri}, fileSource{}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported data source: %s", uri)
+	}
+}
+
+// resolve resolves the uri once, with the timeout of the Resolver
+// the cached Resource, which can be empty, is used for a conditional request
+func (r *Resolver) resolve(ctx context.Context, uri string, cached Resource) (Resource, error) {
+	u, src, err := r.source(uri)
+	if err != nil {
+		return Resource{}, err
+	}
+	if r.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.options.Timeout)
+		defer cancel()
+	}
+	return src.head(ctx, u, cached)
+}
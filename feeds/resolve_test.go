@@ -0,0 +1,207 @@
+package feeds
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testOptions returns ResolverOptions that keep the tests fast
+func testOptions() ResolverOptions {
+	return ResolverOptions{Workers: 4, Timeout: time.Second, Retries: 2, Backoff: time.Millisecond, MaxConnsPerHost: 4}
+}
+
+func TestResolverResolveAll(t *testing.T) {
+	var requests, active, maxActive atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		current := active.Add(1)
+		defer active.Add(-1)
+		for {
+			highest := maxActive.Load()
+			if current <= highest || maxActive.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if r.URL.Path == "/missing.gml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/gml+xml")
+		w.Header().Set("Content-Length", "34987")
+	}))
+	defer server.Close()
+
+	var uris []string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "a", "b"} {
+		uris = append(uris, server.URL+"/"+name+".gml")
+	}
+	uris = append(uris, server.URL+"/missing.gml")
+
+	resources, errs := NewResolver(testOptions()).ResolveAll(context.Background(), uris)
+
+	if len(resources) != 8 {
+		t.Errorf("expected 8 resources, got: %d", len(resources))
+	}
+	if resources[server.URL+"/a.gml"] != (Resource{Length: "34987", Type: "application/gml+xml"}) {
+		t.Errorf("unexpected resource: %+v", resources[server.URL+"/a.gml"])
+	}
+	if _, ok := errs[server.URL+"/missing.gml"]; !ok || len(errs) != 1 {
+		t.Errorf("expected an error for the missing data only, got: %v", errs)
+	}
+	// every unique uri is requested once, a 404 is not retried
+	if requests.Load() != 9 {
+		t.Errorf("expected 9 requests, got: %d", requests.Load())
+	}
+	if maxActive.Load() > 4 {
+		t.Errorf("expected at most 4 concurrent requests, got: %d", maxActive.Load())
+	}
+}
+
+func TestResolverRetries(t *testing.T) {
+	var tests = []struct {
+		failures int
+		retries  int
+		valid    bool
+	}{
+		0: {failures: 0, retries: 0, valid: true},
+		1: {failures: 2, retries: 2, valid: true},
+		2: {failures: 2, retries: 1, valid: false},
+	}
+
+	for k, test := range tests {
+		var mu sync.Mutex
+		failures := test.failures
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			if failures > 0 {
+				failures--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Length", "10")
+		}))
+
+		options := testOptions()
+		options.Retries = test.retries
+		_, err := NewResolver(options).Resolve(context.Background(), server.URL+"/data.gml")
+		if (err == nil) != test.valid {
+			t.Errorf("test: %d, expected valid: %t \ngot: %v", k, test.valid, err)
+		}
+		server.Close()
+	}
+}
+
+func TestResolverTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	options := testOptions()
+	options.Timeout = 20 * time.Millisecond
+	options.Retries = 0
+	if _, err := NewResolver(options).Resolve(context.Background(), server.URL+"/data.gml"); err == nil {
+		t.Errorf("expected a timeout error")
+	}
+}
+
+func TestProcessFeedsResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/x-shapefile")
+		w.Header().Set("Content-Length", "89274")
+	}))
+	defer server.Close()
+
+	input := Feeds{Feeds: []Feed{{
+		ID: "http://xyz.org/data/abc/waternetwork.xml",
+		Entry: []Entry{{
+			ID: "http://xyz.org/data/abc/waternetwork_25832.zip",
+			Link: []Link{
+				{Rel: "alternate", Href: "http://xyz.org/data/abc/waternetwork_25832.zip", Data: sp(server.URL + "/waternetwork_25832.zip")},
+				{Rel: "alternate", Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Type: "application/gml+xml", Data: sp(server.URL + "/waternetwork_25832.gml")},
+			},
+		}},
+	}}}
+	expected := []Link{
+		{Rel: "alternate", Href: "http://xyz.org/data/abc/waternetwork_25832.zip", Type: "application/x-shapefile", Length: "89274", Hreflang: sp("en")},
+		{Rel: "alternate", Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Type: "application/gml+xml", Length: "89274", Hreflang: sp("en")},
+	}
+
+	output, err := ProcessFeeds(input, Options{Resolver: NewResolver(testOptions())})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(output[0].Entry[0].Link, expected) {
+		t.Errorf("expected: %+v \ngot: %+v", expected, output[0].Entry[0].Link)
+	}
+}
+
+func TestProcessFeedsUpdatedFromData(t *testing.T) {
+	lastModified := map[string]string{
+		"/waternetwork_25832.gml": "Sat, 15 Jun 2024 11:12:34 GMT",
+		"/waternetwork_25832.zip": "Sun, 16 Jun 2024 08:00:00 GMT",
+		"/waternetwork_WGS84.gml": "Mon, 01 Jan 2024 00:00:00 GMT",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", lastModified[r.URL.Path])
+	}))
+	defer server.Close()
+
+	input := Feeds{Feeds: []Feed{
+		{
+			ID:    "http://xyz.org/download/en.xml",
+			Entry: []Entry{{ID: "http://xyz.org/data/abc/waternetwork.xml"}},
+		},
+		{
+			ID: "http://xyz.org/data/abc/waternetwork.xml",
+			Entry: []Entry{
+				{
+					ID: "http://xyz.org/data/abc/waternetwork_25832",
+					Link: []Link{
+						{Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Data: sp(server.URL + "/waternetwork_25832.gml")},
+						{Href: "http://xyz.org/data/abc/waternetwork_25832.zip", Data: sp(server.URL + "/waternetwork_25832.zip")},
+					},
+				},
+				{
+					ID:      "http://xyz.org/data/abc/waternetwork_WGS84",
+					Updated: sp("2014-06-15T11:12:34Z"),
+					Link: []Link{
+						{Href: "http://xyz.org/data/abc/waternetwork_WGS84.gml", Data: sp(server.URL + "/waternetwork_WGS84.gml")},
+					},
+				},
+			},
+		},
+	}}
+
+	var tests = []struct {
+		updatedFromData bool
+		expected        []*string
+	}{
+		// service feed, service feed entry, dataset feed, dataset feed entries
+		0: {updatedFromData: false, expected: []*string{sp("2014-06-15T11:12:34Z"), sp("2014-06-15T11:12:34Z"), sp("2014-06-15T11:12:34Z"), nil, sp("2014-06-15T11:12:34Z")}},
+		1: {updatedFromData: true, expected: []*string{sp("2024-06-16T08:00:00Z"), sp("2024-06-16T08:00:00Z"), sp("2024-06-16T08:00:00Z"), sp("2024-06-16T08:00:00Z"), sp("2014-06-15T11:12:34Z")}},
+	}
+
+	for k, test := range tests {
+		output, err := ProcessFeeds(input, Options{UpdatedFromData: test.updatedFromData, Resolver: NewResolver(testOptions())})
+		if err != nil {
+			t.Fatalf("test: %d, unexpected error: %v", k, err)
+		}
+		got := []*string{output[0].Updated, output[0].Entry[0].Updated, output[1].Updated, output[1].Entry[0].Updated, output[1].Entry[1].Updated}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("test: %d, expected: %v \ngot: %v", k, test.expected, got)
+		}
+	}
+}
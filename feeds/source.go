@@ -0,0 +1,29 @@
+package feeds
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// source resolves the `data` of links for a single kind of URI
+type source interface {
+	// head returns the properties of the `data` without reading its content,
+	// the cached Resource is returned when the `data` has not been modified since
+	head(ctx context.Context, uri *url.URL, cached Resource) (Resource, error)
+	// get opens the content of the `data`, the caller closes it
+	get(ctx context.Context, uri *url.URL) (io.ReadCloser, error)
+}
+
+// retryableError marks a failure of a source that is worth retrying, like a timeout or an unavailable server
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,135 @@
+package feeds
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// mediaTypes contains the media types of common download formats,
+// these take precedence over the media types known to the system
+var mediaTypes = map[string]string{
+	`.gml`:     `application/gml+xml`,
+	`.gpkg`:    `application/geopackage+sqlite3`,
+	`.zip`:     `application/zip`,
+	`.xml`:     `application/xml`,
+	`.json`:    `application/json`,
+	`.geojson`: `application/geo+json`,
+	`.csv`:     `text/csv`,
+	`.tif`:     `image/tiff`,
+	`.tiff`:    `image/tiff`,
+}
+
+// fileSource resolves `data` on the local filesystem, the length is the size of the file
+// and the type is derived from the extension or, when unknown, from the content
+type fileSource struct{}
+
+func (s fileSource) head(_ context.Context, uri *url.URL, _ Resource) (Resource, error) {
+	path := filePath(uri)
+	info, err := os.Stat(path)
+	if err != nil {
+		return Resource{}, err
+	}
+	if info.IsDir() {
+		return Resource{}, fmt.Errorf("%s is a directory", path)
+	}
+
+	mediaType, err := fileType(path)
+	if err != nil {
+		return Resource{}, err
+	}
+
+	return Resource{
+		Length:       strconv.FormatInt(info.Size(), 10),
+		Type:         mediaType,
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (s fileSource) get(_ context.Context, uri *url.URL) (io.ReadCloser, error) {
+	return os.Open(filePath(uri))
+}
+
+// list walks the directory and lists all files below it
+func (s fileSource) list(_ context.Context, uri *url.URL) ([]Listed, error) {
+	root := filePath(uri)
+	var listed []Listed
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		listed = append(listed, Listed{
+			URI:      p,
+			Path:     filepath.ToSlash(rel),
+			Resource: Resource{Length: strconv.FormatInt(info.Size(), 10), LastModified: info.ModTime()},
+		})
+		return nil
+	})
+	return listed, err
+}
+
+// filePath returns the local path of a file:// URI or a plain path
+func filePath(uri *url.URL) string {
+	if uri.Scheme == `` || uri.Host == `` || uri.Host == `localhost` {
+		return filepath.FromSlash(uri.Path)
+	}
+	return filepath.FromSlash(uri.Host + uri.Path)
+}
+
+// fileType returns the media type of a file based on its extension or, when unknown, by sniffing its content
+func fileType(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if mediaType, ok := mediaTypes[ext]; ok {
+		return mediaType, nil
+	}
+	if mediaType := mime.TypeByExtension(ext); mediaType != `` {
+		return mediaType, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ``, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, err := f.Read(buf)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return ``, err
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// resolvePath makes a relative `data` path or file:// URI, like file://./data.gml, absolute, relative to the given directory
+func resolvePath(data string, dir string) string {
+	u, err := url.Parse(data)
+	if err != nil || (u.Scheme != `` && u.Scheme != `file`) {
+		return data
+	}
+	path := filepath.FromSlash(data)
+	if u.Scheme == `file` {
+		path = cmp.Or(filepath.FromSlash(u.Opaque), filePath(u))
+	}
+	if filepath.IsAbs(path) {
+		return data
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,76 @@
+package feeds
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadFeedsFileData(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"data/waternetwork_25832.gml": `<?xml version="1.0" encoding="UTF-8"?><FeatureCollection/>`,
+		"data/waternetwork.unknown":   `<?xml version="1.0" encoding="UTF-8"?><FeatureCollection/>`,
+		"data/waternetwork_25832.zip": "PK\x03\x04",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	absolute := filepath.Join(dir, "data", "waternetwork_25832.zip")
+	config := `feeds:
+ - id: "http://xyz.org/data/abc/waternetwork.xml"
+   entry:
+    - id: "http://xyz.org/data/abc/waternetwork_25832.gml"
+      link:
+       - href: "http://xyz.org/data/abc/waternetwork_25832.gml"
+         data: "data/waternetwork_25832.gml"
+       - href: "http://xyz.org/data/abc/waternetwork.unknown"
+         data: "./data/waternetwork.unknown"
+       - href: "http://xyz.org/data/abc/waternetwork_25832.zip"
+         data: "file://` + filepath.ToSlash(absolute) + `"
+         type: "application/x-shapefile"
+       - href: "http://xyz.org/data/abc/waternetwork_25832_copy.gml"
+         data: "file://./data/waternetwork_25832.gml"
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := LoadFeeds(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *fs.Feeds[0].Entry[0].Link[0].Data != filepath.Join(dir, "data", "waternetwork_25832.gml") {
+		t.Errorf("expected the data path to be relative to the config file, got: %s", *fs.Feeds[0].Entry[0].Link[0].Data)
+	}
+	if *fs.Feeds[0].Entry[0].Link[3].Data != filepath.Join(dir, "data", "waternetwork_25832.gml") {
+		t.Errorf("expected the file:// path to be relative to the config file, got: %s", *fs.Feeds[0].Entry[0].Link[3].Data)
+	}
+
+	output, err := ProcessFeeds(fs, Options{Resolver: NewResolver(testOptions())})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Link{
+		{Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Type: "application/gml+xml", Length: "58", Hreflang: sp("en")},
+		{Href: "http://xyz.org/data/abc/waternetwork.unknown", Type: "text/xml; charset=utf-8", Length: "58", Hreflang: sp("en")},
+		{Href: "http://xyz.org/data/abc/waternetwork_25832.zip", Type: "application/x-shapefile", Length: "4", Hreflang: sp("en")},
+		{Href: "http://xyz.org/data/abc/waternetwork_25832_copy.gml", Type: "application/gml+xml", Length: "58", Hreflang: sp("en")},
+	}
+	if !reflect.DeepEqual(output[0].Entry[0].Link, expected) {
+		t.Errorf("expected: %+v \ngot: %+v", expected, output[0].Entry[0].Link)
+	}
+
+	// a missing file is not retried, but reported
+	_, err = ProcessFeeds(Feeds{Feeds: []Feed{{Entry: []Entry{{Link: []Link{{Data: sp(filepath.Join(dir, "missing.gml"))}}}}}}}, Options{})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a not exist error, got: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// httpSource resolves `data` with a HEAD request
+type httpSource struct {
+	client *http.Client
+}
+
+func (s httpSource) head(ctx context.Context, uri *url.URL, cached Resource) (Resource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri.String(), nil)
+	if err != nil {
+		return Resource{}, err
+	}
+	conditional(req.Header, cached)
+	return doHead(s.client, req, cached)
+}
+
+func (s httpSource) get(ctx context.Context, uri *url.URL) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := do(s.client, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// do does a request and returns the response when its status is not an error
+func do(client *http.Client, req *http.Request) (*http.Response, error) {
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, &retryableError{err: err}
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		res.Body.Close()
+		err := fmt.Errorf("%s %s returned %s", req.Method, req.URL, res.Status)
+		if res.StatusCode >= http.StatusInternalServerError || res.StatusCode == http.StatusTooManyRequests {
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// conditional sets the headers of a conditional request for the validators of the cached Resource
+func conditional(header http.Header, cached Resource) {
+	if cached.ETag != `` {
+		header.Set("If-None-Match", cached.ETag)
+	}
+	if !cached.LastModified.IsZero() {
+		header.Set("If-Modified-Since", cached.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// doHead does a HEAD request and returns the Resource described by the response headers,
+// or the cached Resource when the response is 304 Not Modified
+func doHead(client *http.Client, req *http.Request, cached Resource) (Resource, error) {
+	res, err := do(client, req)
+	if err != nil {
+		return Resource{}, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode == http.StatusNotModified {
+		return cached, nil
+	}
+
+	// a missing or invalid Last-Modified results in a zero time
+	lastModified, _ := http.ParseTime(res.Header.Get("Last-Modified"))
+
+	return Resource{
+		Length:       res.Header.Get("Content-Length"),
+		Type:         res.Header.Get("Content-Type"),
+		ETag:         res.Header.Get("ETag"),
+		LastModified: lastModified,
+	}, nil
+}
@@ -0,0 +1,275 @@
+package feeds
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// emptyPayloadHash is the SHA-256 of an empty request body
+	emptyPayloadHash = `e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855`
+	defaultRegion    = `us-east-1`
+)
+
+// S3 configures the access to S3 compatible object storage, which is used for `s3://bucket/key` data
+// empty fields are taken from the environment variables AWS_ENDPOINT_URL_S3, AWS_REGION,
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+//
+//nolint:tagliatelle
+type S3 struct {
+	Endpoint     string `yaml:"endpoint,omitempty"`
+	Region       string `yaml:"region,omitempty"`
+	AccessKey    string `yaml:"access_key,omitempty"`
+	SecretKey    string `yaml:"secret_key,omitempty"`
+	SessionToken string `yaml:"session_token,omitempty"`
+}
+
+// withEnvironment returns the S3 config completed with the environment variables and defaults
+func (s S3) withEnvironment() S3 {
+	fill := func(value *string, envs ...string) {
+		for _, env := range envs {
+			if *value == `` {
+				*value = os.Getenv(env)
+			}
+		}
+	}
+	fill(&s.Endpoint, `AWS_ENDPOINT_URL_S3`, `AWS_ENDPOINT_URL`)
+	fill(&s.Region, `AWS_REGION`, `AWS_DEFAULT_REGION`)
+	fill(&s.AccessKey, `AWS_ACCESS_KEY_ID`)
+	fill(&s.SecretKey, `AWS_SECRET_ACCESS_KEY`)
+	fill(&s.SessionToken, `AWS_SESSION_TOKEN`)
+
+	if s.Region == `` {
+		s.Region = defaultRegion
+	}
+	if s.Endpoint == `` {
+		s.Endpoint = `https://s3.` + s.Region + `.amazonaws.com`
+	}
+	return s
+}
+
+// s3Source resolves `data` with a signed HeadObject request and downloads it with a GetObject request, objects are addressed path-style: endpoint/bucket/key
+type s3Source struct {
+	client *http.Client
+	config S3
+}
+
+func (s s3Source) head(ctx context.Context, uri *url.URL, cached Resource) (Resource, error) {
+	header := http.Header{}
+	conditional(header, cached)
+	req, err := s.request(ctx, http.MethodHead, uri.Host, strings.TrimPrefix(uri.Path, `/`), nil, header)
+	if err != nil {
+		return Resource{}, err
+	}
+	return doHead(s.client, req, cached)
+}
+
+func (s s3Source) get(ctx context.Context, uri *url.URL) (io.ReadCloser, error) {
+	req, err := s.request(ctx, http.MethodGet, uri.Host, strings.TrimPrefix(uri.Path, `/`), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := do(s.client, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// listBucketResult is the response of a ListObjectsV2 request
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		ETag         string    `xml:"ETag"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// list lists the objects under the prefix with ListObjectsV2 requests, following the continuation tokens
+func (s s3Source) list(ctx context.Context, uri *url.URL) ([]Listed, error) {
+	prefix := strings.TrimPrefix(uri.Path, `/`)
+	if prefix != `` && !strings.HasSuffix(prefix, `/`) {
+		prefix += `/`
+	}
+
+	var listed []Listed
+	query := url.Values{`list-type`: {`2`}, `prefix`: {prefix}}
+	for {
+		req, err := s.request(ctx, http.MethodGet, uri.Host, ``, query, nil)
+		if err != nil {
+			return nil, err
+		}
+		res, err := do(s.client, req)
+		if err != nil {
+			return nil, err
+		}
+		var result listBucketResult
+		err = xml.NewDecoder(res.Body).Decode(&result)
+		res.Body.Close()
+		if err != nil {
+			return nil, &retryableError{err: fmt.Errorf("could not decode the listing of %s: %w", uri, err)}
+		}
+
+		for _, object := range result.Contents {
+			if strings.HasSuffix(object.Key, `/`) {
+				continue
+			}
+			listed = append(listed, Listed{
+				URI:  `s3://` + uri.Host + `/` + object.Key,
+				Path: strings.TrimPrefix(object.Key, prefix),
+				Resource: Resource{
+					Length:       strconv.FormatInt(object.Size, 10),
+					ETag:         object.ETag,
+					LastModified: object.LastModified,
+				},
+			})
+		}
+		if !result.IsTruncated || result.NextContinuationToken == `` {
+			return listed, nil
+		}
+		query.Set(`continuation-token`, result.NextContinuationToken)
+	}
+}
+
+// request creates a signed request for an object or, without a key, for a bucket, the given headers are signed too
+func (s s3Source) request(ctx context.Context, method, bucket, key string, query url.Values, header http.Header) (*http.Request, error) {
+	if bucket == `` {
+		return nil, errors.New(`missing bucket in s3 uri`)
+	}
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	u := endpoint.JoinPath(bucket)
+	if key != `` {
+		u = u.JoinPath(key)
+	}
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	// without credentials the request is anonymous, which works for public buckets
+	if s.config.AccessKey != `` {
+		signV4(req, s.config, time.Now())
+	}
+	return req, nil
+}
+
+// signV4 signs a request without a body with AWS Signature Version 4,
+// the host and all headers present on the request are signed
+func signV4(req *http.Request, config S3, now time.Time) {
+	amzDate := now.UTC().Format(`20060102T150405Z`)
+	date := amzDate[:8]
+
+	req.Header.Set(`X-Amz-Date`, amzDate)
+	req.Header.Set(`X-Amz-Content-Sha256`, emptyPayloadHash)
+	if config.SessionToken != `` {
+		req.Header.Set(`X-Amz-Security-Token`, config.SessionToken)
+	}
+
+	headers := map[string]string{`host`: req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, `,`))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + `:` + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, `;`)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsEscape(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := date + `/` + config.Region + `/s3/aws4_request`
+	stringToSign := strings.Join([]string{`AWS4-HMAC-SHA256`, amzDate, scope, sha256Hex(canonicalRequest)}, "\n")
+
+	key := hmacSHA256([]byte(`AWS4`+config.SecretKey), date)
+	key = hmacSHA256(key, config.Region)
+	key = hmacSHA256(key, `s3`)
+	key = hmacSHA256(key, `aws4_request`)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set(`Authorization`, `AWS4-HMAC-SHA256 Credential=`+config.AccessKey+`/`+scope+
+		`, SignedHeaders=`+signedHeaders+`, Signature=`+signature)
+}
+
+// canonicalQuery returns the query sorted by key and encoded as AWS expects
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, awsEscape(key, true)+`=`+awsEscape(value, true))
+		}
+	}
+	return strings.Join(parts, `&`)
+}
+
+// awsEscape percent-encodes everything but the unreserved characters of RFC 3986 and, for a path, the slashes
+func awsEscape(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := range len(value) {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteString(`%` + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
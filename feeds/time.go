@@ -0,0 +1,79 @@
+package feeds
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// parseTime parses a RFC 3339 timestamp, with or without fractional seconds and with any timezone offset
+func parseTime(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+// parseInterval parses a RFC 3339 timestamp or an ISO 8601 interval of two RFC 3339 timestamps (start/end)
+func parseInterval(value string) (time.Time, time.Time, error) {
+	start, end, isInterval := strings.Cut(value, `/`)
+	if !isInterval {
+		t, err := parseTime(value)
+		return t, t, err
+	}
+
+	startTime, err := parseTime(start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endTime, err := parseTime(end)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if endTime.Before(startTime) {
+		return time.Time{}, time.Time{}, errors.New(`end of interval is before its start`)
+	}
+	return startTime, endTime, nil
+}
+
+// formatTime returns the canonical form of a timestamp: RFC 3339 in UTC
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// normalizeTime returns the canonical form of a RFC 3339 timestamp,
+// invalid timestamps are returned as is and reported by Valid
+func normalizeTime(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	t, err := parseTime(*value)
+	if err != nil {
+		return value
+	}
+	normalized := formatTime(t)
+	return &normalized
+}
+
+// normalizeInterval returns the canonical form of a RFC 3339 timestamp or ISO 8601 interval,
+// invalid values are returned as is and reported by Valid
+func normalizeInterval(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	if !strings.Contains(*value, `/`) {
+		return normalizeTime(value)
+	}
+	start, end, err := parseInterval(*value)
+	if err != nil {
+		return value
+	}
+	normalized := formatTime(start) + `/` + formatTime(end)
+	return &normalized
+}
+
+// UpdatedTime returns the 'updated' of the Feed, false when it is missing or not a valid timestamp
+func (f *Feed) UpdatedTime() (time.Time, bool) {
+	if f.Updated == nil {
+		return time.Time{}, false
+	}
+	t, err := parseTime(*f.Updated)
+	return t, err == nil
+}
@@ -0,0 +1,73 @@
+package feeds
+
+import (
+	"testing"
+)
+
+func TestNormalizeInterval(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		0: {input: "2024-03-01T10:00:00Z", expected: "2024-03-01T10:00:00Z"},
+		1: {input: "2024-03-01T10:00:00+01:00", expected: "2024-03-01T09:00:00Z"},
+		2: {input: "2024-03-01T10:00:00.250-02:00", expected: "2024-03-01T12:00:00.25Z"},
+		3: {input: "2024-01-01T00:00:00+01:00/2024-12-31T23:59:59+01:00", expected: "2023-12-31T23:00:00Z/2024-12-31T22:59:59Z"},
+		4: {input: "2024-12-31T00:00:00Z/2024-01-01T00:00:00Z", expected: "2024-12-31T00:00:00Z/2024-01-01T00:00:00Z"},
+		5: {input: "2024-03-01", expected: "2024-03-01"},
+	}
+
+	for k, test := range tests {
+		output := normalizeInterval(&test.input)
+		if *output != test.expected {
+			t.Errorf("test: %d, expected: %s \ngot: %s", k, test.expected, *output)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	var tests = []struct {
+		input string
+		valid bool
+	}{
+		0: {input: "2024-03-01T10:00:00Z", valid: true},
+		1: {input: "2024-03-01T10:00:00+01:00", valid: true},
+		2: {input: "2024-01-01T00:00:00Z/2024-12-31T23:59:59Z", valid: true},
+		3: {input: "2024-12-31T00:00:00Z/2024-01-01T00:00:00Z", valid: false},
+		4: {input: "2024-01-01T00:00:00Z/", valid: false},
+		5: {input: "0000-00-00T00:00:00Z", valid: false},
+		6: {input: "2024-03-01T10:00:00", valid: false},
+	}
+
+	for k, test := range tests {
+		_, _, err := parseInterval(test.input)
+		if (err == nil) != test.valid {
+			t.Errorf("test: %d, expected valid: %t \ngot: %v", k, test.valid, err)
+		}
+	}
+}
+
+func TestRecentUpdatedEntry(t *testing.T) {
+	var tests = []struct {
+		input    Feed
+		expected *string
+	}{
+		0: {input: Feed{}, expected: nil},
+		1: {input: Feed{Entry: []Entry{
+			{Updated: sp("2024-03-01T10:00:00Z")},
+			{Updated: sp("2024-03-01T10:30:00+01:00")},
+		}}, expected: sp("2024-03-01T10:00:00Z")},
+		2: {input: Feed{Entry: []Entry{
+			{Updated: sp("2024-03-01T09:00:00-02:00")},
+			{Updated: sp("2024-03-01T10:00:00Z")},
+			{Updated: nil},
+		}}, expected: sp("2024-03-01T09:00:00-02:00")},
+	}
+
+	for k, test := range tests {
+		output := test.input.recentUpdatedEntry()
+		if (output == nil) != (test.expected == nil) || (output != nil && *output != *test.expected) {
+			t.Errorf("test: %d, expected: %v \ngot: %v", k, test.expected, output)
+		}
+	}
+}
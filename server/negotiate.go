@@ -0,0 +1,84 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+)
+
+// preference is a value of an Accept or Accept-Language header with its quality
+type preference struct {
+	value   string
+	quality float64
+}
+
+// parsePreferences parses a header like `text/html, application/json;q=0.9`, a value without q has quality 1
+func parsePreferences(header string) []preference {
+	var preferences []preference
+	for part := range strings.SplitSeq(header, `,`) {
+		value, params, _ := strings.Cut(part, `;`)
+		p := preference{value: strings.ToLower(strings.TrimSpace(value)), quality: 1}
+		if p.value == `` {
+			continue
+		}
+		for param := range strings.SplitSeq(params, `;`) {
+			if name, q, ok := strings.Cut(strings.TrimSpace(param), `=`); ok && strings.TrimSpace(name) == `q` {
+				if quality, err := strconv.ParseFloat(strings.TrimSpace(q), 64); err == nil {
+					p.quality = quality
+				}
+			}
+		}
+		preferences = append(preferences, p)
+	}
+	return preferences
+}
+
+// negotiate returns the index of the offer with the highest quality in the header, the first offer wins a tie,
+// it returns -1 when none of the offers is acceptable, an empty header accepts the first offer
+func negotiate(header string, offers []string, match func(value, offer string) (int, bool)) int {
+	if strings.TrimSpace(header) == `` {
+		return 0
+	}
+	preferences := parsePreferences(header)
+	best, bestQuality := -1, 0.0
+	for i, offer := range offers {
+		// the most specific matching value determines the quality of an offer
+		quality, specificity := 0.0, -1
+		for _, p := range preferences {
+			if s, ok := match(p.value, strings.ToLower(offer)); ok && s > specificity {
+				quality, specificity = p.quality, s
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = i, quality
+		}
+	}
+	return best
+}
+
+// matchMediaType matches a media range like `*/*`, `application/*` or `application/json`
+func matchMediaType(value, offer string) (int, bool) {
+	switch {
+	case value == offer:
+		return 2, true
+	case value == `*/*`:
+		return 0, true
+	case strings.HasSuffix(value, `/*`) && strings.HasPrefix(offer, strings.TrimSuffix(value, `*`)):
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// matchLanguage matches a language range like `*`, `de` or `de-DE`, a range matches its subtags and the other way around
+func matchLanguage(value, offer string) (int, bool) {
+	switch {
+	case value == offer:
+		return 2, true
+	case value == `*`:
+		return 0, true
+	case strings.HasPrefix(offer, value+`-`) || strings.HasPrefix(value, offer+`-`):
+		return 1, true
+	default:
+		return 0, false
+	}
+}
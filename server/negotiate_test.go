@@ -0,0 +1,32 @@
+package server
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	mediaTypes := []string{"application/atom+xml", "text/html", "application/json"}
+	languages := []string{"en", "de", "nl"}
+
+	var tests = []struct {
+		header   string
+		offers   []string
+		match    func(value, offer string) (int, bool)
+		expected int
+	}{
+		0: {header: "", offers: mediaTypes, match: matchMediaType, expected: 0},
+		1: {header: "text/html", offers: mediaTypes, match: matchMediaType, expected: 1},
+		2: {header: "application/json;q=0.9, text/html;q=0.5", offers: mediaTypes, match: matchMediaType, expected: 2},
+		3: {header: "text/*, application/json;q=0.5", offers: mediaTypes, match: matchMediaType, expected: 1},
+		// the most specific range determines the quality
+		4: {header: "*/*, application/atom+xml;q=0", offers: mediaTypes, match: matchMediaType, expected: 1},
+		5: {header: "image/png", offers: mediaTypes, match: matchMediaType, expected: -1},
+		6: {header: "de-DE, en;q=0.8", offers: languages, match: matchLanguage, expected: 1},
+		7: {header: "fr, *;q=0.1", offers: languages, match: matchLanguage, expected: 0},
+		8: {header: "NL;q=0.9, fr", offers: languages, match: matchLanguage, expected: 2},
+	}
+
+	for k, test := range tests {
+		if got := negotiate(test.header, test.offers, test.match); got != test.expected {
+			t.Errorf("test: %d, expected: %d \ngot: %d", k, test.expected, got)
+		}
+	}
+}
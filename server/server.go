@@ -0,0 +1,391 @@
+// Package server answers the OpenSearch operations of a pre-defined ATOM download service
+// (Get Download Service Metadata, Describe Spatial Dataset and Get Spatial Dataset) from the processed feeds
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/pdok/atom-generator/feeds"
+)
+
+const (
+	atomType       = `application/atom+xml`
+	opensearchType = `application/opensearchdescription+xml`
+)
+
+// format is a representation in which a feed is served
+type format struct {
+	name        string
+	contentType string
+	render      func(*feeds.Feed) ([]byte, error)
+}
+
+// formats are the representations of a feed, the first one is the default
+var formats = []format{
+	{name: `atom`, contentType: atomType, render: (*feeds.Feed).GenerateATOM},
+	{name: `html`, contentType: `text/html; charset=utf-8`, render: (*feeds.Feed).GenerateHTML},
+	{name: `json`, contentType: `application/json`, render: (*feeds.Feed).GenerateJSON},
+}
+
+// accepted maps the media types of the Accept header to the formats, XML is served as ATOM
+var accepted = []struct {
+	mediaType string
+	format    int
+}{
+	{atomType, 0},
+	{`application/xml`, 0},
+	{`text/xml`, 0},
+	{`text/html`, 1},
+	{`application/json`, 2},
+}
+
+// Options configures the Server
+type Options struct {
+	// Static is a directory with static files, like the stylesheet, that is served for the paths that are not a feed
+	Static string
+	// Stream streams the `data` of a download instead of redirecting to its href
+	Stream bool
+	// Data maps the href of the links to their `data`, it is used when Stream is set
+	Data map[string]string
+	// Resolver opens the `data` of a download when Stream is set
+	Resolver *feeds.Resolver
+}
+
+// Server serves the ATOM feeds by the paths of their 'id' and 'self' links
+// and answers the OpenSearch operations on the service feeds, the representation and language
+// of a feed are negotiated with the Accept and Accept-Language headers
+type Server struct {
+	feeds []feeds.Feed
+	// index maps the id and self hrefs to the feeds, paths maps the paths of these hrefs
+	index map[string]int
+	paths map[string]int
+	// rendered contains the representations of the feeds, by feed and format
+	rendered   [][][]byte
+	opensearch map[string][]byte
+	static     http.Handler
+	options    Options
+}
+
+// New creates a Server for the processed feeds
+func New(processed []feeds.Feed, options Options) (*Server, error) {
+	s := &Server{
+		feeds:      processed,
+		index:      make(map[string]int),
+		paths:      make(map[string]int),
+		opensearch: make(map[string][]byte),
+		options:    options,
+	}
+	if options.Static != `` {
+		s.static = http.FileServer(http.Dir(options.Static))
+	}
+
+	for i, f := range processed {
+		representations, err := render(f)
+		if err != nil {
+			return nil, err
+		}
+		s.rendered = append(s.rendered, representations)
+
+		hrefs := []string{f.ID}
+		for _, l := range f.Links(`self`) {
+			hrefs = append(hrefs, l.Href)
+		}
+		for _, href := range hrefs {
+			if _, ok := s.index[href]; !ok {
+				s.index[href] = i
+			}
+			if p, ok := hrefPath(href); ok {
+				s.paths[p] = i
+			}
+		}
+	}
+
+	// the language variants of a service feed share one OpenSearch description
+	for href, d := range feeds.OpenSearchDescriptions(processed) {
+		body, err := d.GenerateXML()
+		if err != nil {
+			return nil, err
+		}
+		if p, ok := hrefPath(href); ok {
+			s.opensearch[p] = body
+		}
+	}
+	return s, nil
+}
+
+// render returns the representations of a feed in the order of the formats
+func render(f feeds.Feed) ([][]byte, error) {
+	representations := make([][]byte, 0, len(formats))
+	for _, ft := range formats {
+		// the feed is copied, because rendering ATOM removes the stylesheet
+		c := f
+		body, err := ft.render(&c)
+		if err != nil {
+			return nil, err
+		}
+		representations = append(representations, body)
+	}
+	return representations, nil
+}
+
+// ServeHTTP serves the feeds, the OpenSearch descriptions and the static files,
+// a request with a spatial_dataset_identifier_code on a service feed is an OpenSearch operation
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set(`Allow`, `GET, HEAD`)
+		http.Error(w, `method not allowed`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	if body, ok := s.opensearch[r.URL.Path]; ok {
+		w.Header().Set(`Content-Type`, opensearchType)
+		_, _ = w.Write(body)
+		return
+	}
+	feed, ok := s.paths[r.URL.Path]
+	switch {
+	case !ok && s.static != nil:
+		s.static.ServeHTTP(w, r)
+	case !ok:
+		http.NotFound(w, r)
+	case s.feeds[feed].Kind() == feeds.ServiceFeed:
+		s.serviceOperation(w, r, feed)
+	default:
+		s.serveFeed(w, r, s.translation(feed, r), nil)
+	}
+}
+
+// serviceOperation answers Get Download Service Metadata, Describe Spatial Dataset and Get Spatial Dataset
+func (s *Server) serviceOperation(w http.ResponseWriter, r *http.Request, service int) {
+	query := r.URL.Query()
+	code := query.Get(`spatial_dataset_identifier_code`)
+
+	// Get Download Service Metadata returns the service feed in the requested language
+	if code == `` {
+		s.serveFeed(w, r, s.translation(service, r), nil)
+		return
+	}
+
+	dataset, ok := s.dataset(s.translation(service, r), code, query.Get(`spatial_dataset_identifier_namespace`), r)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no dataset with spatial_dataset_identifier_code %s", code), http.StatusNotFound)
+		return
+	}
+	dataset = s.translation(dataset, r)
+
+	// Describe Spatial Dataset returns the dataset feed
+	crs := query.Get(`crs`)
+	if crs == `` {
+		s.serveFeed(w, r, dataset, nil)
+		return
+	}
+
+	// Get Spatial Dataset returns the download of the entry in the requested CRS
+	var entries []feeds.Entry
+	var links []feeds.Link
+	for _, entry := range s.feeds[dataset].Entry {
+		if !slices.ContainsFunc(entry.Category, func(c feeds.Category) bool { return c.Term == crs }) {
+			continue
+		}
+		entries = append(entries, entry)
+		for _, l := range entry.Link {
+			if l.Rel == `alternate` || l.Rel == `section` {
+				links = append(links, l)
+			}
+		}
+	}
+	switch {
+	case len(entries) == 0:
+		http.Error(w, fmt.Sprintf("dataset %s is not available in CRS %s", code, crs), http.StatusNotFound)
+	case len(links) == 1:
+		s.download(w, r, links[0])
+	default:
+		// a download of multiple files is described by a feed with only the matching entries
+		s.serveFeed(w, r, dataset, entries)
+	}
+}
+
+// translation returns the index of the translation of a feed in the requested language,
+// the translations are the feeds that are linked with an 'alternate' link with a hreflang
+func (s *Server) translation(feed int, r *http.Request) int {
+	variants := []int{feed}
+	languages := []string{s.lang(feed)}
+	for _, l := range s.feeds[feed].Links(`alternate`) {
+		if i, ok := s.index[l.Href]; ok && l.Hreflang != nil && !slices.Contains(variants, i) {
+			variants = append(variants, i)
+			languages = append(languages, *l.Hreflang)
+		}
+	}
+
+	if i := preferredLanguage(r, languages); i >= 0 {
+		return variants[i]
+	}
+	return feed
+}
+
+// preferredLanguage returns the index of the requested language in the languages, or -1,
+// the language parameter of the OpenSearch operations takes precedence over the Accept-Language header
+func preferredLanguage(r *http.Request, languages []string) int {
+	if language := r.URL.Query().Get(`language`); language != `` {
+		return slices.Index(languages, language)
+	}
+	return negotiate(r.Header.Get(`Accept-Language`), languages, matchLanguage)
+}
+
+// lang returns the language of a feed
+func (s *Server) lang(feed int) string {
+	if s.feeds[feed].Lang == nil {
+		return ``
+	}
+	return *s.feeds[feed].Lang
+}
+
+// dataset returns the dataset feed of the service feed entry with the spatial_dataset_identifier,
+// the 'alternate' link in the requested language takes precedence
+func (s *Server) dataset(service int, code, namespace string, r *http.Request) (int, bool) {
+	for _, entry := range s.feeds[service].Entry {
+		if entry.SpatialDatasetIdentifierCode == nil || *entry.SpatialDatasetIdentifierCode != code {
+			continue
+		}
+		if namespace != `` && (entry.SpatialDatasetIdentifierNamespace == nil || *entry.SpatialDatasetIdentifierNamespace != namespace) {
+			continue
+		}
+
+		var found []int
+		var languages []string
+		for _, l := range entry.Link {
+			i, ok := s.index[l.Href]
+			if !ok || l.Rel != `alternate` || s.feeds[i].Kind() != feeds.DatasetFeed {
+				continue
+			}
+			found = append(found, i)
+			if l.Hreflang != nil {
+				languages = append(languages, *l.Hreflang)
+			} else {
+				languages = append(languages, ``)
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+		if i := preferredLanguage(r, languages); i >= 0 {
+			return found[i], true
+		}
+		return found[0], true
+	}
+	return -1, false
+}
+
+// download streams the `data` of the link or redirects to its href
+func (s *Server) download(w http.ResponseWriter, r *http.Request, link feeds.Link) {
+	data, ok := s.options.Data[link.Href]
+	if !s.options.Stream || !ok {
+		http.Redirect(w, r, link.Href, http.StatusFound)
+		return
+	}
+
+	body, err := s.options.Resolver.Open(r.Context(), data)
+	if err != nil {
+		log.Printf("could not open %s: %v", data, err)
+		http.Error(w, `download is not available`, http.StatusBadGateway)
+		return
+	}
+	defer body.Close()
+
+	if link.Type != `` {
+		w.Header().Set(`Content-Type`, link.Type)
+	}
+	if link.Length != `` {
+		w.Header().Set(`Content-Length`, link.Length)
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("could not stream %s: %v", data, err)
+	}
+}
+
+// serveFeed serves a feed in the representation of the Accept header, with an ETag and Last-Modified
+// derived from its 'updated', entries replaces the entries of the feed when it is not nil
+func (s *Server) serveFeed(w http.ResponseWriter, r *http.Request, feed int, entries []feeds.Entry) {
+	offers := make([]string, 0, len(accepted))
+	for _, a := range accepted {
+		offers = append(offers, a.mediaType)
+	}
+	offer := negotiate(r.Header.Get(`Accept`), offers, matchMediaType)
+	if offer < 0 {
+		http.Error(w, `not acceptable, the feeds are available as application/atom+xml, text/html and application/json`, http.StatusNotAcceptable)
+		return
+	}
+	ft := accepted[offer].format
+
+	f := s.feeds[feed]
+	body := s.rendered[feed][ft]
+	if entries != nil {
+		f.Entry = entries
+		var err error
+		if body, err = formats[ft].render(&f); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	header := w.Header()
+	header.Set(`Content-Type`, formats[ft].contentType)
+	header.Set(`Vary`, `Accept, Accept-Language`)
+	if lang := s.lang(feed); lang != `` {
+		header.Set(`Content-Language`, lang)
+	}
+	updated, ok := f.UpdatedTime()
+	if ok {
+		header.Set(`ETag`, etag(f, formats[ft].name))
+	}
+	// ServeContent answers If-None-Match and If-Modified-Since with 304 Not Modified
+	http.ServeContent(w, r, ``, updated, bytes.NewReader(body))
+}
+
+// etag identifies a representation of a feed by its id, 'updated' and entries,
+// so it changes when the 'updated' of the feed changes
+func etag(f feeds.Feed, format string) string {
+	hash := sha256.New()
+	hash.Write([]byte(f.ID + "\n" + *f.Updated))
+	for _, entry := range f.Entry {
+		hash.Write([]byte("\n" + entry.ID))
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:8]) + `-` + format + `"`
+}
+
+// hrefPath returns the path of a href, on which it is served
+func hrefPath(href string) (string, bool) {
+	u, err := url.Parse(href)
+	if err != nil || u.Path == `` || strings.HasSuffix(u.Path, `/`) {
+		return ``, false
+	}
+	return u.Path, true
+}
+
+// DataByHref maps the href of the links of the entries to their `data`, the feeds are configured feeds
+// in which the generators are expanded, see feeds.Feeds.Generated
+func DataByHref(config feeds.Feeds) map[string]string {
+	data := make(map[string]string)
+	for _, f := range config.Feeds {
+		for _, entry := range f.Entry {
+			for _, l := range entry.Link {
+				if l.Data != nil {
+					data[l.Href] = *l.Data
+				}
+			}
+		}
+	}
+	return data
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pdok/atom-generator/feeds"
+)
+
+func sp(s string) *string {
+	return &s
+}
+
+func testServer(t *testing.T, stream bool) *httptest.Server {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "waternetwork_25832.gml"), []byte("<FeatureCollection/>"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "style.xsl"), []byte("<xsl:stylesheet/>"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	service := feeds.Feed{
+		ID:     "http://xyz.org/download/en.xml",
+		Title:  "XYZ Example INSPIRE Download Service",
+		Search: &feeds.Link{Href: "http://xyz.org/search/opensearchdescription.xml"},
+		Link:   []feeds.Link{{Href: "http://xyz.org/download/de.xml", Rel: "alternate", Type: "application/atom+xml", Hreflang: sp("de")}},
+		Entry: []feeds.Entry{{
+			ID:                                "http://xyz.org/data/abc/waternetwork.xml",
+			SpatialDatasetIdentifierCode:      sp("wn_id1"),
+			SpatialDatasetIdentifierNamespace: sp("http://xyz.org/"),
+			Link: []feeds.Link{
+				{Href: "http://xyz.org/data/abc/waternetwork.xml", Rel: "alternate", Type: "application/atom+xml", Hreflang: sp("en")},
+				{Href: "http://xyz.org/data/abc/waternetwork_de.xml", Rel: "alternate", Type: "application/atom+xml", Hreflang: sp("de")},
+			},
+		}},
+	}
+	translation := service
+	translation.ID, translation.Lang, translation.Title = "http://xyz.org/download/de.xml", sp("de"), "XYZ Beispiel INSPIRE Download Service"
+	translation.Link = []feeds.Link{{Href: "http://xyz.org/download/en.xml", Rel: "alternate", Type: "application/atom+xml", Hreflang: sp("en")}}
+
+	config := feeds.Feeds{Feeds: []feeds.Feed{service, translation, {
+		ID: "http://xyz.org/data/abc/waternetwork.xml",
+		Entry: []feeds.Entry{
+			{ID: "http://xyz.org/data/abc/waternetwork_25832.gml", CRS: []int{25832}, Updated: sp("2021-03-31T13:45:03Z"), Link: []feeds.Link{
+				{Href: "http://xyz.org/data/abc/waternetwork_25832.gml", Rel: "alternate", Type: "application/gml+xml", Length: "20", Data: sp(filepath.Join(dir, "waternetwork_25832.gml"))},
+			}},
+			{ID: "http://xyz.org/data/abc/waternetwork_4258", CRS: []int{4258}, Link: []feeds.Link{
+				{Href: "http://xyz.org/data/abc/waternetwork_4258_1.gml", Rel: "section", Type: "application/gml+xml"},
+				{Href: "http://xyz.org/data/abc/waternetwork_4258_2.gml", Rel: "section", Type: "application/gml+xml"},
+			}},
+		},
+	}, {
+		ID:   "http://xyz.org/data/abc/waternetwork_de.xml",
+		Lang: sp("de"),
+		Entry: []feeds.Entry{
+			{ID: "http://xyz.org/data/abc/waternetwork_de_25832.gml", CRS: []int{25832}, Link: []feeds.Link{
+				{Href: "http://xyz.org/data/abc/waternetwork_de_25832.gml", Rel: "alternate", Type: "application/gml+xml"},
+			}},
+		},
+	}}}
+
+	options := feeds.DefaultResolverOptions()
+	options.Retries = 0
+	resolver := feeds.NewResolver(options)
+	processed, err := feeds.ProcessFeeds(config, feeds.Options{SkipData: true, Resolver: resolver})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(processed, Options{Static: dir, Stream: stream, Data: DataByHref(config), Resolver: resolver})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return httptest.NewServer(s)
+}
+
+func TestServer(t *testing.T) {
+	var tests = []struct {
+		method      string
+		path        string
+		header      map[string]string
+		stream      bool
+		status      int
+		location    string
+		contentType string
+		contains    string
+	}{
+		// Get Download Service Metadata
+		0: {path: "/download/en.xml", status: http.StatusOK, contains: "<title>XYZ Example INSPIRE Download Service</title>"},
+		1: {path: "/download/en.xml?language=de", status: http.StatusOK, contains: "<title>XYZ Beispiel INSPIRE Download Service</title>"},
+		2: {path: "/search/opensearchdescription.xml", status: http.StatusOK, contains: "<OpenSearchDescription"},
+		// Describe Spatial Dataset
+		3: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id1&spatial_dataset_identifier_namespace=http://xyz.org/&language=en",
+			status: http.StatusOK, contains: "<id>http://xyz.org/data/abc/waternetwork.xml</id>"},
+		4: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id2", status: http.StatusNotFound},
+		5: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id1&spatial_dataset_identifier_namespace=http://abc.org/", status: http.StatusNotFound},
+		// Get Spatial Dataset
+		6: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id1&crs=http://www.opengis.net/def/crs/EPSG/0/25832",
+			status: http.StatusFound, location: "http://xyz.org/data/abc/waternetwork_25832.gml"},
+		7: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id1&crs=http://www.opengis.net/def/crs/EPSG/0/25832",
+			stream: true, status: http.StatusOK, contains: "<FeatureCollection/>"},
+		8: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id1&crs=http://www.opengis.net/def/crs/EPSG/0/4258",
+			status: http.StatusOK, contains: "waternetwork_4258_2.gml"},
+		9: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id1&crs=http://www.opengis.net/def/crs/EPSG/0/3035", status: http.StatusNotFound},
+		// static files and the dataset feed
+		10: {path: "/style.xsl", status: http.StatusOK, contains: "<xsl:stylesheet/>"},
+		11: {path: "/data/abc/waternetwork.xml", status: http.StatusOK, contains: "waternetwork_25832.gml"},
+		12: {path: "/missing.xml", status: http.StatusNotFound},
+		13: {method: http.MethodPost, path: "/download/en.xml", status: http.StatusMethodNotAllowed},
+		// content negotiation
+		14: {path: "/data/abc/waternetwork.xml", header: map[string]string{"Accept": "text/html,application/xhtml+xml,*/*;q=0.8"},
+			status: http.StatusOK, contentType: "text/html; charset=utf-8", contains: "<!DOCTYPE html>"},
+		15: {path: "/data/abc/waternetwork.xml", header: map[string]string{"Accept": "application/json"},
+			status: http.StatusOK, contentType: "application/json", contains: `"id": "http://xyz.org/data/abc/waternetwork.xml"`},
+		16: {path: "/data/abc/waternetwork.xml", header: map[string]string{"Accept": "text/xml"},
+			status: http.StatusOK, contentType: "application/atom+xml", contains: "<feed"},
+		17: {path: "/data/abc/waternetwork.xml", header: map[string]string{"Accept": "image/png"}, status: http.StatusNotAcceptable},
+		18: {path: "/download/en.xml", header: map[string]string{"Accept-Language": "de-DE, en;q=0.5"},
+			status: http.StatusOK, contains: "<title>XYZ Beispiel INSPIRE Download Service</title>"},
+		19: {path: "/download/en.xml?language=en", header: map[string]string{"Accept-Language": "de"},
+			status: http.StatusOK, contains: "<title>XYZ Example INSPIRE Download Service</title>"},
+		20: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id1", header: map[string]string{"Accept-Language": "en;q=0.1, de"},
+			status: http.StatusOK, contains: "waternetwork_de_25832.gml"},
+		21: {path: "/download/en.xml?spatial_dataset_identifier_code=wn_id1&language=en", header: map[string]string{"Accept-Language": "de"},
+			status: http.StatusOK, contains: "waternetwork_25832.gml"},
+		// conditional requests
+		22: {path: "/data/abc/waternetwork.xml", header: map[string]string{"If-Modified-Since": "Wed, 31 Mar 2021 13:45:03 GMT"}, status: http.StatusNotModified},
+		23: {path: "/data/abc/waternetwork.xml", header: map[string]string{"If-Modified-Since": "Tue, 30 Mar 2021 13:45:03 GMT"},
+			status: http.StatusOK, contains: "<feed"},
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	for k, test := range tests {
+		srv := testServer(t, test.stream)
+		method := test.method
+		if method == `` {
+			method = http.MethodGet
+		}
+		req, err := http.NewRequest(method, srv.URL+test.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, value := range test.header {
+			req.Header.Set(name, value)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		srv.Close()
+
+		if resp.StatusCode != test.status {
+			t.Errorf("test: %d, expected status: %d \ngot: %d %s", k, test.status, resp.StatusCode, body)
+		}
+		if test.contentType != `` && resp.Header.Get("Content-Type") != test.contentType {
+			t.Errorf("test: %d, expected content type: %s \ngot: %s", k, test.contentType, resp.Header.Get("Content-Type"))
+		}
+		if location := resp.Header.Get("Location"); location != test.location {
+			t.Errorf("test: %d, expected location: %s \ngot: %s", k, test.location, location)
+		}
+		if !strings.Contains(string(body), test.contains) {
+			t.Errorf("test: %d, expected %s in: %s", k, test.contains, body)
+		}
+		if strings.Contains(string(body), `data="`) {
+			t.Errorf("test: %d, the data of the links is published: %s", k, body)
+		}
+	}
+}
+
+func TestServerETag(t *testing.T) {
+	srv := testServer(t, false)
+	defer srv.Close()
+
+	get := func(header map[string]string) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/data/abc/waternetwork.xml", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, value := range header {
+			req.Header.Set(name, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	atom := get(nil)
+	etag := atom.Header.Get("ETag")
+	if etag == `` || atom.Header.Get("Last-Modified") != "Wed, 31 Mar 2021 13:45:03 GMT" {
+		t.Fatalf("expected an ETag and Last-Modified, got: %v", atom.Header)
+	}
+	if resp := get(map[string]string{"If-None-Match": etag}); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected 304 for the same ETag, got: %d", resp.StatusCode)
+	}
+	// every representation has its own ETag
+	if resp := get(map[string]string{"If-None-Match": etag, "Accept": "application/json"}); resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Errorf("expected 200 with another ETag for JSON, got: %d %s", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}